@@ -0,0 +1,243 @@
+// anomaly.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Calibration is the method setting the threshold of an anomaly detector
+type Calibration string
+
+const (
+	// QuantileCalibration sets the threshold to the empirical quantile
+	// of the training scores
+	QuantileCalibration Calibration = "quantile"
+	// PeaksOverThreshold models the excesses of the scores over a high
+	// empirical quantile by a generalized Pareto distribution and
+	// extrapolates the threshold from it (SPOT, Siffer et al., 2017)
+	PeaksOverThreshold Calibration = "pot"
+)
+
+// anomalyEpsilon bounds the uniforms away from 0 and 1 so that the
+// density of the copula remains defined
+const anomalyEpsilon = 1e-10
+
+// AnomalyOptions gathers the parameters of an anomaly detector
+type AnomalyOptions struct {
+	// Calibration is the method setting the threshold
+	Calibration Calibration
+	// Risk is the probability that a normal observation is flagged
+	Risk float64
+	// TailLevel is the level of the empirical quantile of the scores
+	// above which the peaks are modelled (PeaksOverThreshold only)
+	TailLevel float64
+	// Fit are the options of the fit of the copula
+	Fit *FitOptions
+}
+
+// DefaultAnomalyOptions returns the default parameters of an anomaly detector
+func DefaultAnomalyOptions() *AnomalyOptions {
+	return &AnomalyOptions{
+		Calibration: PeaksOverThreshold,
+		Risk:        1e-3,
+		TailLevel:   0.98,
+		Fit:         DefaultFitOptions(),
+	}
+}
+
+// AnomalyDetector flags the observations whose joint behaviour is unusual.
+// The observations are transformed by the margins and scored by the
+// opposite of the log-density of the copula: only the dependence is
+// assessed, an extreme value of a single coordinate is not an anomaly
+// when the other ones follow it. An observation is flagged when its score
+// exceeds the threshold.
+type AnomalyDetector struct {
+	// Copula is the dependence structure
+	Copula *ArchimedeanCopula
+	// Margins are the univariate distributions of every coordinate
+	// (the empirical cdfs of the training observations when nil)
+	Margins []Margin
+	// Threshold is the score above which an observation is flagged
+	Threshold float64
+	// Options are the parameters of the detector
+	Options *AnomalyOptions
+
+	// peaks over threshold state
+	initial float64
+	peaks   []float64
+	count   int
+	tail    *GeneralizedPareto
+}
+
+// NewAnomalyDetector returns an anomaly detector made of the given copula
+// and margins (nil margins are replaced by the empirical cdfs of the
+// training observations). It must be fitted before use.
+func NewAnomalyDetector(copula *ArchimedeanCopula, margins []Margin, opts *AnomalyOptions) *AnomalyDetector {
+	if opts == nil {
+		opts = DefaultAnomalyOptions()
+	}
+	return &AnomalyDetector{
+		Copula:    copula,
+		Margins:   margins,
+		Threshold: math.NaN(),
+		Options:   opts,
+	}
+}
+
+// Score computes the anomaly score -log c(F_1(x_1), ..., F_d(x_d)) of an
+// observation (+Inf when the density is not defined). It returns NaN when
+// the observation does not have one coordinate per margin.
+func (ad *AnomalyDetector) Score(x []float64) float64 {
+	if len(x) != len(ad.Margins) {
+		return math.NaN()
+	}
+	u := make([]float64, len(x))
+	for j, m := range ad.Margins {
+		u[j] = math.Max(anomalyEpsilon, math.Min(1.-anomalyEpsilon, m.CDF(x[j])))
+	}
+	s := -ad.Copula.LogPdf(u)
+	if math.IsNaN(s) {
+		return math.Inf(1)
+	}
+	return s
+}
+
+// Scores computes the anomaly score of every observation
+func (ad *AnomalyDetector) Scores(X *mat.Dense) ([]float64, error) {
+	n, d := X.Dims()
+	if len(ad.Margins) != d {
+		return nil, fmt.Errorf("%d margins are given while the observations have %d columns", len(ad.Margins), d)
+	}
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = ad.Score(X.RawRowView(i))
+	}
+	return scores, nil
+}
+
+// Fit estimates the empirical margins (when they are not given) and the
+// parameter of the copula on the training observations, which are assumed
+// to be normal, and calibrates the threshold on their scores. The copula
+// is updated. With the PeaksOverThreshold calibration, an error is returned
+// when fewer than 2 scores exceed the initial threshold (constant scores).
+func (ad *AnomalyDetector) Fit(X *mat.Dense) (*FitResult, error) {
+	opts := ad.Options
+	if !(opts.Risk > 0. && opts.Risk < 1.) {
+		return nil, fmt.Errorf("The risk must lie in (0, 1) (got %f)", opts.Risk)
+	}
+	n, d := X.Dims()
+	if ad.Margins == nil {
+		ad.Margins = make([]Margin, d)
+		for j := 0; j < d; j++ {
+			ad.Margins[j] = NewECDF(rawCol(X, j))
+		}
+	}
+	U, err := applyMargins(X, ad.Margins)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ad.Copula.FitWithOptions(U, opts.Fit)
+	if err != nil {
+		return result, err
+	}
+
+	scores, _ := ad.Scores(X)
+	sort.Float64s(scores)
+	switch opts.Calibration {
+	case QuantileCalibration:
+		ad.Threshold = stat.Quantile(1.-opts.Risk, stat.Empirical, scores, nil)
+		ad.count = n
+		return result, nil
+	case PeaksOverThreshold:
+		if !(opts.TailLevel > 0. && opts.TailLevel < 1.) {
+			return nil, fmt.Errorf("The tail level must lie in (0, 1) (got %f)", opts.TailLevel)
+		}
+		if opts.Risk >= 1.-opts.TailLevel {
+			return nil, fmt.Errorf("The risk (%f) must be lower than the tail proportion (%f)", opts.Risk, 1.-opts.TailLevel)
+		}
+		ad.initial = stat.Quantile(opts.TailLevel, stat.Empirical, scores, nil)
+		ad.peaks = make([]float64, 0)
+		for _, s := range scores {
+			if s > ad.initial && !math.IsInf(s, 1) {
+				ad.peaks = append(ad.peaks, s-ad.initial)
+			}
+		}
+		if len(ad.peaks) < 2 {
+			// typically at the independence boundary where every score is null
+			return result, fmt.Errorf("The fitted copula gives constant scores (%d peaks over the initial threshold %f)", len(ad.peaks), ad.initial)
+		}
+		ad.count = n
+		return result, ad.calibrateTail()
+	default:
+		return nil, fmt.Errorf("Unknown calibration '%s'", opts.Calibration)
+	}
+}
+
+// calibrateTail fits the generalized Pareto distribution of the peaks and
+// computes the threshold z such that P(score > z) = Risk, i.e.
+// z = t + G^(-1)(1 - Risk n / N_t) where t is the initial threshold, n the
+// number of normal observations and N_t the number of peaks
+func (ad *AnomalyDetector) calibrateTail() error {
+	tail, err := FitGeneralizedPareto(ad.peaks)
+	if err != nil {
+		return err
+	}
+	ad.tail = tail
+	ratio := ad.Options.Risk * float64(ad.count) / float64(len(ad.peaks))
+	ad.Threshold = ad.initial + tail.Quantile(1.-math.Min(1., ratio))
+	return nil
+}
+
+// Detect flags the observations whose score exceeds the threshold
+// (batch mode, the detector is not modified)
+func (ad *AnomalyDetector) Detect(X *mat.Dense) ([]bool, error) {
+	if math.IsNaN(ad.Threshold) {
+		return nil, errors.New("The detector is not fitted")
+	}
+	scores, err := ad.Scores(X)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]bool, len(scores))
+	for i, s := range scores {
+		flags[i] = s > ad.Threshold
+	}
+	return flags, nil
+}
+
+// Update processes a new observation and tells whether it is an anomaly
+// (streaming mode). With the PeaksOverThreshold calibration, the normal
+// observations whose score exceeds the initial threshold are added to
+// the peaks and the threshold is updated. The copula and the margins are
+// left unchanged.
+func (ad *AnomalyDetector) Update(x []float64) (bool, error) {
+	if math.IsNaN(ad.Threshold) {
+		return false, errors.New("The detector is not fitted")
+	}
+	if len(x) != len(ad.Margins) {
+		return false, fmt.Errorf("The observation has %d coordinates instead of %d", len(x), len(ad.Margins))
+	}
+	s := ad.Score(x)
+	if s > ad.Threshold {
+		return true, nil
+	}
+	ad.count++
+	if ad.Options.Calibration == PeaksOverThreshold && s > ad.initial {
+		ad.peaks = append(ad.peaks, s-ad.initial)
+		return false, ad.calibrateTail()
+	}
+	return false, nil
+}
+
+// Tail returns the generalized Pareto distribution of the peaks
+// (nil with the QuantileCalibration)
+func (ad *AnomalyDetector) Tail() *GeneralizedPareto {
+	return ad.tail
+}
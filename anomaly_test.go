@@ -0,0 +1,152 @@
+// anomaly_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitAnomaly(t *testing.T) {
+	title("Anomaly detection")
+}
+
+// exponentialObservations draws observations with unit exponential
+// margins coupled by a clayton copula (theta = 2.1)
+func exponentialObservations(n int, seed int64) *mat.Dense {
+	X := NewCopula("clayton", 2.1).sampleWith(n, 2, rand.New(rand.NewSource(seed)))
+	X.Apply(func(i, j int, v float64) float64 { return -math.Log1p(-v) }, X)
+	return X
+}
+
+// discordantObservations returns observations where one coordinate is
+// low while the other one is high (unusual under a clayton copula)
+func discordantObservations(n int) *mat.Dense {
+	X := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		u := 0.005 + 0.01*float64(i)/float64(n)
+		X.SetRow(i, []float64{-math.Log1p(-u), -math.Log(u)})
+	}
+	return X
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	train := exponentialObservations(5000, 1)
+	normal := exponentialObservations(2000, 2)
+	anomalies := discordantObservations(20)
+
+	for _, calibration := range []Calibration{QuantileCalibration, PeaksOverThreshold} {
+		opts := DefaultAnomalyOptions()
+		opts.Calibration = calibration
+		ad := NewAnomalyDetector(NewCopula("clayton", 1.), nil, opts)
+		if _, err := ad.Fit(train); err != nil {
+			t.Fatal(err)
+		}
+
+		checkTitle("Checking anomaly detection (" + string(calibration) + ")...")
+		flags, err := ad.Detect(anomalies)
+		if err != nil {
+			t.Fatal(err)
+		}
+		detected := 0
+		for _, f := range flags {
+			if f {
+				detected++
+			}
+		}
+		flags, _ = ad.Detect(normal)
+		alarms := 0
+		for _, f := range flags {
+			if f {
+				alarms++
+			}
+		}
+		if detected != 20 || alarms > 10 || math.Abs(ad.Copula.Theta()-2.1) > 0.2 {
+			t.Errorf("Bad detection (%d/20 anomalies, %d false alarms)", detected, alarms)
+			testERROR()
+		} else {
+			testOK()
+		}
+	}
+}
+
+func TestAnomalyDetectorConstantScores(t *testing.T) {
+	// the fit reaches the independence boundary where every score is null
+	X := NewCopula("gumbel", 1.01).sampleWith(300, 2, rand.New(rand.NewSource(1)))
+	opts := DefaultAnomalyOptions()
+	opts.Calibration = PeaksOverThreshold
+	ad := NewAnomalyDetector(NewCopula("gumbel", 1.5), nil, opts)
+
+	checkTitle("Checking constant scores...")
+	_, err := ad.Fit(X)
+	if err == nil || !strings.Contains(err.Error(), "constant scores") || !math.IsNaN(ad.Threshold) {
+		t.Errorf("The fit should report the constant scores (%v)", err)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestAnomalyDetectorStreaming(t *testing.T) {
+	ad := NewAnomalyDetector(NewCopula("clayton", 1.), nil, nil)
+	if _, err := ad.Fit(exponentialObservations(2000, 3)); err != nil {
+		t.Fatal(err)
+	}
+	initial := ad.Threshold
+	peaks := len(ad.peaks)
+
+	checkTitle("Checking streaming detection...")
+	stream := exponentialObservations(3000, 4)
+	anomalies := discordantObservations(10)
+	detected, alarms := 0, 0
+	for i := 0; i < 3000; i++ {
+		if i%300 == 0 {
+			flag, err := ad.Update(anomalies.RawRowView(i / 300))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flag {
+				detected++
+			}
+		}
+		flag, err := ad.Update(stream.RawRowView(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flag {
+			alarms++
+		}
+	}
+	if detected != 10 || alarms > 15 {
+		t.Errorf("Bad detection (%d/10 anomalies, %d false alarms)", detected, alarms)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking threshold update...")
+	if len(ad.peaks) <= peaks || ad.Threshold == initial || ad.Tail() == nil {
+		t.Errorf("The peaks should update the threshold (%d peaks)", len(ad.peaks))
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking anomaly detector arguments...")
+	_, err1 := ad.Update([]float64{1., 2., 3.})
+	_, err2 := NewAnomalyDetector(NewCopula("clayton", 1.), nil, nil).Detect(stream)
+	opts := DefaultAnomalyOptions()
+	opts.Risk = 0.05
+	_, err3 := NewAnomalyDetector(NewCopula("clayton", 1.), nil, opts).Fit(stream)
+	s1, s2 := ad.Score([]float64{1.}), ad.Score([]float64{1., 2., 3.})
+	if err1 == nil || err2 == nil || err3 == nil || !math.IsNaN(s1) || !math.IsNaN(s2) {
+		t.Errorf("The detector should return errors")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
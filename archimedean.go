@@ -6,9 +6,11 @@
 package gopula
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
 
 	"gonum.org/v1/gonum/mat"
@@ -53,26 +55,149 @@ type FitResult struct {
 	Theta float64
 	// LogLikelihhod is the correspond log-likelihood (the maximum)
 	LogLikelihood float64
-	// UpperBound is the 95% upper confidence bound
+	// StdErr is the standard error of the estimated parameter
+	// (computed from the observed information)
+	StdErr float64
+	// SandwichStdErr is the standard error of the estimated parameter
+	// given by the Godambe information. It accounts for the estimation of
+	// the margins (FitCML, FitIFM), for the misspecification of the
+	// pairwise likelihood or for the weighting of the observations in the
+	// density power divergence (StdErr is then equal to it). It is NaN
+	// when the margins are considered as known.
+	SandwichStdErr float64
+	// Level is the level of the confidence bounds (0.95 by default)
+	Level float64
+	// UpperBound is the upper profile-likelihood confidence bound
+	// (NaN when the profile bounds are not computed)
 	UpperBound float64
-	// LowerBound is the 95% upper confidence bound
+	// LowerBound is the lower profile-likelihood confidence bound
+	// (NaN when the profile bounds are not computed)
 	LowerBound float64
+	// UpperUnbounded is true when the upper profile bound is not
+	// reached within ThetaBounds (UpperBound is then the domain bound)
+	UpperUnbounded bool
+	// LowerUnbounded is true when the lower profile bound is not
+	// reached within ThetaBounds (LowerBound is then the domain bound)
+	LowerUnbounded bool
 	// Evals is the number of function evaluations
 	Evals int
 	// Message describes whether the fit has suceeded
 	Message string
+	// Weights are the relative weights of the observations in the robust
+	// fits (nil otherwise): c(U_i)^alpha divided by their mean for the
+	// density power divergence, 1 for the kept observations and 0 for the
+	// trimmed ones for the trimmed likelihood
+	Weights []float64
+	// Rejected is the proportion of observations discarded by the robust
+	// fits (weight lower than 0.1)
+	Rejected float64
+}
+
+// WaldBounds returns the Wald confidence interval of 𝜃 at the given
+// level (𝜃 ± z * StdErr)
+func (fr *FitResult) WaldBounds(level float64) (float64, float64) {
+	z := distuv.UnitNormal.Quantile(0.5 * (1. + level))
+	return fr.Theta - z*fr.StdErr, fr.Theta + z*fr.StdErr
+}
+
+// SandwichBounds returns the Wald confidence interval of 𝜃 at the given
+// level using the sandwich standard error (𝜃 ± z * SandwichStdErr)
+func (fr *FitResult) SandwichBounds(level float64) (float64, float64) {
+	z := distuv.UnitNormal.Quantile(0.5 * (1. + level))
+	return fr.Theta - z*fr.SandwichStdErr, fr.Theta + z*fr.SandwichStdErr
 }
 
 func (fr *FitResult) String() string {
-	format := "%8s %.6f\n%8s %.6f\n%8s [%.3f, %.3f]\n%8s %d\n%8s %s"
-	return fmt.Sprintf(format,
+	format := "%8s %.6f\n%8s %.6f\n%8s %.6f\n%8s [%.3f, %.3f]\n%8s [%s, %s]\n%8s %d\n%8s %s"
+	level := fmt.Sprintf("%.0f%%", 100.*fr.Level)
+	lower := fmt.Sprintf("%.3f", fr.LowerBound)
+	if fr.LowerUnbounded {
+		lower = "<" + lower
+	}
+	upper := fmt.Sprintf("%.3f", fr.UpperBound)
+	if fr.UpperUnbounded {
+		upper = ">" + upper
+	}
+	waldDown, waldUp := fr.WaldBounds(fr.Level)
+	out := fmt.Sprintf(format,
 		"ℓ", fr.LogLikelihood,
 		"𝜃", fr.Theta,
-		"95%", fr.LowerBound, fr.UpperBound,
+		"σ", fr.StdErr,
+		"Wald", waldDown, waldUp,
+		level, lower, upper,
 		"Evals", fr.Evals,
 		"Message", fr.Message)
+	if !math.IsNaN(fr.SandwichStdErr) {
+		sDown, sUp := fr.SandwichBounds(fr.Level)
+		out += fmt.Sprintf("\n%8s %.6f\n%8s [%.3f, %.3f]",
+			"σ*", fr.SandwichStdErr,
+			"Sandwich", sDown, sUp)
+	}
+	return out
+}
+
+// FitMethod is the name of an estimation procedure
+type FitMethod string
+
+const (
+	// MaximumLikelihood maximizes the full likelihood
+	MaximumLikelihood FitMethod = "ml"
+	// PairwiseLikelihood maximizes the pairwise composite likelihood
+	PairwiseLikelihood FitMethod = "pairwise"
+	// DensityPowerDivergence minimizes the density power divergence
+	// (robust to outliers)
+	DensityPowerDivergence FitMethod = "mdpd"
+	// TrimmedLikelihood maximizes the likelihood of the observations
+	// having the largest densities (robust to outliers)
+	TrimmedLikelihood FitMethod = "trimmed"
+	// MinimumCvM minimizes the Cramér-von Mises distance between
+	// the empirical copula and the copula
+	MinimumCvM FitMethod = "cvm"
+	// MinimumL2 minimizes the L2 distance between the empirical
+	// copula and the copula
+	MinimumL2 FitMethod = "l2"
+)
+
+// FitOptions gathers the settings of the fit procedure
+type FitOptions struct {
+	// Method is the estimation procedure (MaximumLikelihood when empty)
+	Method FitMethod
+	// Level is the level of the confidence bounds (0.95 when it
+	// does not lie in (0, 1))
+	Level float64
+	// Profile enables the computation of the profile-likelihood
+	// confidence bounds (they are rather expensive)
+	Profile bool
+	// Pairs is the number of column pairs sampled by the pairwise
+	// likelihood (all the pairs when 0)
+	Pairs int
+	// Seed makes the sampling of the pairs reproducible
+	Seed int64
+	// Censoring describes the censored coordinates of the observations
+	// (the likelihood of exactly observed data is used when nil)
+	Censoring *Censoring
+	// Alpha is the power of the density power divergence (0.25 when
+	// not positive). The larger, the more robust and the less efficient.
+	// Values above 0.5 should be avoided for tail dependent families
+	// (the integral of c^(1+alpha) may diverge).
+	Alpha float64
+	// Trim is the proportion of observations discarded by the trimmed
+	// likelihood (0.1 when not positive). It is also the breakdown point.
+	Trim float64
+	// WarmStart searches the estimate around the current theta first
+	// (useful when the fits are repeated on close samples)
+	WarmStart bool
 }
 
+// DefaultFitOptions returns the options used by Fit
+func DefaultFitOptions() *FitOptions {
+	return &FitOptions{Method: MaximumLikelihood, Level: 0.95, Profile: true}
+}
+
+// ErrUnbounded is returned when a profile-likelihood bound
+// is not reached within ThetaBounds
+var ErrUnbounded = errors.New("The profile likelihood bound is not reached within the domain")
+
 // ArchimedeanCopula is a generic structure defining
 // an archimedean copula
 type ArchimedeanCopula struct {
@@ -93,6 +218,14 @@ type ArchimedeanCopuler interface {
 	LogPdf(vector []float64, theta float64) float64
 }
 
+// RadialQuantiler is an interface that a generator may implement when
+// the quantile function of its radial part is known (it is then used by
+// RadialPpf and Sample instead of a numerical inversion). It returns NaN
+// when the dimension is not supported.
+type RadialQuantiler interface {
+	RadialPpf(p float64, dim int, theta float64) float64
+}
+
 // NewCopula returns a new copula according to the desired family
 func NewCopula(family string, theta float64) *ArchimedeanCopula {
 	switch family {
@@ -157,6 +290,21 @@ func (arch *ArchimedeanCopula) LogPdf(vector []float64) float64 {
 	return arch.copula.LogPdf(vector, arch.theta)
 }
 
+// Psi computes the generator of the copula
+func (arch *ArchimedeanCopula) Psi(t float64) float64 {
+	return arch.copula.Psi(t, arch.theta)
+}
+
+// PsiInv computes the inverse of the generator of the copula
+func (arch *ArchimedeanCopula) PsiInv(u float64) float64 {
+	return arch.copula.PsiInv(u, arch.theta)
+}
+
+// PsiD computes the d-th derivative of the generator of the copula
+func (arch *ArchimedeanCopula) PsiD(d int, t float64) float64 {
+	return arch.copula.PsiD(d, t, arch.theta)
+}
+
 // LogLikelihood computes the log-likelihood of a batch of
 // observations given the underlying archimedean copula
 func (arch *ArchimedeanCopula) LogLikelihood(M *mat.Dense) float64 {
@@ -175,26 +323,47 @@ func (arch *ArchimedeanCopula) LogLikelihood(M *mat.Dense) float64 {
 
 // ConfidenceBounds compute the upper and lower confidence bounds
 // at given level (level = 1-alpha = 0.95 in practice). The parameter
-// theta must be the fitted value.
-func (arch *ArchimedeanCopula) ConfidenceBounds(M *mat.Dense, level float64) (float64, float64) {
-	ll := arch.LogLikelihood(M)
+// theta must be the fitted value. When a bound is not reached within
+// ThetaBounds, the corresponding domain bound is returned along
+// with ErrUnbounded.
+func (arch *ArchimedeanCopula) ConfidenceBounds(M *mat.Dense, level float64) (float64, float64, error) {
+	thetaDown, downUnbounded, err := arch.profileBound(arch.logLikelihoodToMinimize, M, level, false)
+	if err != nil {
+		return thetaDown, math.NaN(), err
+	}
+	thetaUp, upUnbounded, err := arch.profileBound(arch.logLikelihoodToMinimize, M, level, true)
+	if err != nil {
+		return thetaDown, thetaUp, err
+	}
+	if downUnbounded || upUnbounded {
+		return thetaDown, thetaUp, ErrUnbounded
+	}
+	return thetaDown, thetaUp, nil
+}
+
+// profileBound computes either the lower or the upper profile-likelihood
+// bound, f being the opposite of the log-likelihood. It also returns
+// whether the bound is reached within the domain.
+func (arch *ArchimedeanCopula) profileBound(f ObjectiveFunction, args interface{}, level float64, upper bool) (float64, bool, error) {
+	ll := -f(arch.theta, args)
 	cs := distuv.ChiSquared{K: 1}
 	q := cs.Quantile(level)
-	fun := func(x float64, args interface{}) float64 {
-		return arch.logLikelihoodToMinimize(x, M) + (ll - q/2)
+	fun := func(x float64, _ interface{}) float64 {
+		return f(x, args) + (ll - q/2)
 	}
+
 	maxDown, maxUp := arch.copula.ThetaBounds()
-	// maxUp = arch.theta + 2.
-	thetaUp, err := Bisection(fun, nil, arch.theta, maxUp, 1e-8)
-	if err != nil {
-		fmt.Println(err)
-		fmt.Println(fun(arch.theta, nil), fun(maxUp, nil))
+	limit := maxDown
+	if upper {
+		limit = maxUp
 	}
-	thetaDown, err := Bisection(fun, nil, maxDown, arch.theta, 1e-8)
-	if err != nil {
-		fmt.Println(err)
+	// the root is not bracketed: the likelihood does not
+	// decrease enough until the bound of the domain
+	if !(fun(limit, nil) > 0.) {
+		return limit, true, nil
 	}
-	return thetaDown, thetaUp
+	bound, err := Bisection(fun, nil, arch.theta, limit, 1e-8)
+	return bound, false, err
 }
 
 func (arch *ArchimedeanCopula) logLikelihoodToMinimize(theta float64, args interface{}) float64 {
@@ -215,30 +384,160 @@ func (arch *ArchimedeanCopula) logLikelihoodToMinimize(theta float64, args inter
 }
 
 // Fit estimates the best theta parameter through maximum likelihood
-// estimation according to the input observations
+// estimation according to the input observations (DefaultFitOptions).
+// The error returned by FitWithOptions, if any, is reported in the
+// Message of the result.
 func (arch *ArchimedeanCopula) Fit(M *mat.Dense) *FitResult {
-	msg := ""
-	a, b := arch.copula.ThetaBounds()
-	thetaBest, llhood, feval, err := BrentMinimizer(arch.logLikelihoodToMinimize, M, a, b, 1e-8)
-	if math.Min(math.Abs(thetaBest-a), math.Abs(thetaBest-b)) < 1e-2 {
-		msg = "Falling back to BFGS. "
-		thetaBest, llhood, feval, err = BFGS(arch.logLikelihoodToMinimize, M, 0.5*(a+b))
+	result, err := arch.FitWithOptions(M, DefaultFitOptions())
+	if err != nil && !strings.Contains(result.Message, err.Error()) {
+		result.Message += " (" + err.Error() + ")"
+	}
+	return result
+}
+
+// FitWithOptions estimates the best theta parameter according to the
+// input observations and the given options (DefaultFitOptions when nil)
+func (arch *ArchimedeanCopula) FitWithOptions(M *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	if opts == nil {
+		opts = DefaultFitOptions()
+	}
+	if !(opts.Level > 0. && opts.Level < 1.) {
+		o := *opts
+		o.Level = 0.95
+		opts = &o
+	}
+	switch opts.Method {
+	case MaximumLikelihood, "":
+	case PairwiseLikelihood:
+		return arch.fitPairwise(M, opts)
+	case DensityPowerDivergence:
+		return arch.fitDensityPowerDivergence(M, opts)
+	case TrimmedLikelihood:
+		return arch.fitTrimmed(M, opts)
+	case MinimumCvM, MinimumL2:
+		return arch.fitDistance(M, opts)
+	default:
+		return nil, fmt.Errorf("Unknown fit method '%s'", opts.Method)
+	}
+
+	// opposite of the log-likelihood to minimize
+	var objective ObjectiveFunction = arch.logLikelihoodToMinimize
+	var args interface{} = M
+	if opts.Censoring != nil {
+		if err := opts.Censoring.check(M); err != nil {
+			return nil, err
+		}
+		objective = arch.censoredLogLikelihoodToMinimize
+		args = &censoredArgs{M: M, censoring: opts.Censoring}
 	}
+
+	thetaBest, llhood, feval, msg, err := arch.searchTheta(objective, args, opts)
 	if err != nil {
 		msg += "Error: " + err.Error()
 	} else {
 		msg += "Success"
 	}
-	// thetaBest, llhood, nit := BFGS(arch.logLikelihoodToMinimize, M, 0.5*(a+b))
 	arch.theta = thetaBest
-	down, up := arch.ConfidenceBounds(M, 0.95)
-	return &FitResult{
-		Theta:         thetaBest,
-		LogLikelihood: -llhood,
-		UpperBound:    up,
-		LowerBound:    down,
-		Evals:         feval,
-		Message:       msg}
+
+	result := &FitResult{
+		Theta:          thetaBest,
+		LogLikelihood:  -llhood,
+		StdErr:         math.NaN(),
+		SandwichStdErr: math.NaN(),
+		Level:          opts.Level,
+		UpperBound:     math.NaN(),
+		LowerBound:     math.NaN(),
+		Evals:          feval,
+		Message:        msg}
+	if info := arch.observedInformation(objective, args); info > 0. {
+		result.StdErr = 1. / math.Sqrt(info)
+	}
+
+	if opts.Profile {
+		down, downUnbounded, errDown := arch.profileBound(objective, args, opts.Level, false)
+		up, upUnbounded, errUp := arch.profileBound(objective, args, opts.Level, true)
+		result.LowerBound, result.LowerUnbounded = down, downUnbounded
+		result.UpperBound, result.UpperUnbounded = up, upUnbounded
+		if err == nil && errDown != nil {
+			err = errDown
+		}
+		if err == nil && errUp != nil {
+			err = errUp
+		}
+	}
+	return result, err
+}
+
+// maximizeLikelihood returns the maximum likelihood estimate of theta,
+// the opposite of the reached log-likelihood, the number of
+// function evaluations and a message about the used method
+func (arch *ArchimedeanCopula) maximizeLikelihood(M *mat.Dense) (float64, float64, int, string, error) {
+	return arch.minimizeTheta(arch.logLikelihoodToMinimize, M)
+}
+
+// minimizeTheta minimizes an objective function of theta over ThetaBounds.
+// It returns the minimizer, the minimum, the number of function
+// evaluations and a message about the used method
+func (arch *ArchimedeanCopula) minimizeTheta(f ObjectiveFunction, args interface{}) (float64, float64, int, string, error) {
+	msg := ""
+	a, b := arch.copula.ThetaBounds()
+	thetaBest, fBest, feval, err := BrentMinimizer(f, args, a, b, 1e-8)
+	if math.Min(math.Abs(thetaBest-a), math.Abs(thetaBest-b)) < 1e-2 {
+		msg = "Falling back to BFGS. "
+		thetaBest, fBest, feval, err = BFGS(f, args, 0.5*(a+b))
+	}
+	return thetaBest, fBest, feval, msg, err
+}
+
+// searchTheta minimizes an objective function of theta like minimizeTheta.
+// With opts.WarmStart, the minimum is first searched on a bracket around
+// the current theta: the whole domain is only explored when the minimizer
+// lies on the border of the bracket.
+func (arch *ArchimedeanCopula) searchTheta(f ObjectiveFunction, args interface{}, opts *FitOptions) (float64, float64, int, string, error) {
+	if !opts.WarmStart {
+		return arch.minimizeTheta(f, args)
+	}
+	a, b := arch.copula.ThetaBounds()
+	width := 0.5 * math.Max(math.Abs(arch.theta), 1.)
+	lower, upper := math.Max(a, arch.theta-width), math.Min(b, arch.theta+width)
+	thetaBest, fBest, feval, err := BrentMinimizer(f, args, lower, upper, 1e-8)
+	margin := 1e-2 * (upper - lower)
+	if err == nil && thetaBest-lower > margin && upper-thetaBest > margin {
+		return thetaBest, fBest, feval, "Warm start. ", nil
+	}
+	thetaBest, fBest, evals, msg, err := arch.minimizeTheta(f, args)
+	return thetaBest, fBest, feval + evals, msg, err
+}
+
+// StdErr computes the standard error of the current theta
+// from the observed information, i.e. the opposite of the second
+// derivative of the log-likelihood. It returns NaN when the
+// information is not positive (theta at the boundary for instance).
+func (arch *ArchimedeanCopula) StdErr(M *mat.Dense) float64 {
+	info := arch.ObservedInformation(M)
+	if !(info > 0.) {
+		return math.NaN()
+	}
+	return 1. / math.Sqrt(info)
+}
+
+// ObservedInformation computes the opposite of the second derivative
+// of the log-likelihood at the current theta
+func (arch *ArchimedeanCopula) ObservedInformation(M *mat.Dense) float64 {
+	return arch.observedInformation(arch.logLikelihoodToMinimize, M)
+}
+
+// observedInformation computes the second derivative of the objective
+// function f (opposite of a log-likelihood) at the current theta
+func (arch *ArchimedeanCopula) observedInformation(f ObjectiveFunction, args interface{}) float64 {
+	a, b := arch.copula.ThetaBounds()
+	// the initial step must keep the evaluations inside the domain
+	h := math.Min(0.1*math.Max(math.Abs(arch.theta), 0.1), 0.5*math.Min(arch.theta-a, b-arch.theta))
+	if !(h > 0.) {
+		return math.NaN()
+	}
+	d2, _ := SecondDerivative(f, args, arch.theta, h)
+	return d2
 }
 
 // RadialCdf computes the cdf of the radial part of the ArchimeanCopula
@@ -248,12 +547,13 @@ func (arch *ArchimedeanCopula) RadialCdf(x float64, dim int) float64 {
 	}
 
 	dimF := float64(dim)
+	// (-1)^(d-1) psi^(d-1) is non-negative
 	coeff := 1.
-	if (dim-1)%2 == 0 {
+	if (dim-1)%2 == 1 {
 		coeff = -1.
 	}
 	psid := arch.copula.PsiD(dim-1, x, arch.theta)
-	cdfx := 1. - arch.copula.Psi(x, arch.theta) + coeff*math.Pow(x, dimF-1.)*math.Max(0., psid)/float64(factorial(dim-1))
+	cdfx := 1. - arch.copula.Psi(x, arch.theta) - math.Pow(x, dimF-1.)*math.Max(0., coeff*psid)/float64(factorial(dim-1))
 	// if psid > 0 {
 	// 	if dim%2 == 0 {
 	// 		cdfx = cdfx + math.Pow(x, dimF-1.)*psid/float64(factorial(dim-1))
@@ -273,6 +573,11 @@ func (arch *ArchimedeanCopula) RadialCdf(x float64, dim int) float64 {
 
 // RadialPpf computes the quantile zp verifying P(X<zp) = p
 func (arch *ArchimedeanCopula) RadialPpf(p float64, dim int) float64 {
+	if rq, ok := arch.copula.(RadialQuantiler); ok {
+		if zp := rq.RadialPpf(p, dim, arch.theta); !math.IsNaN(zp) {
+			return zp
+		}
+	}
 	c := 0.95
 	if p > 0. && p < 1. {
 		// fun := func(z float64, args interface{}) float64 {
@@ -343,3 +648,24 @@ func (arch *ArchimedeanCopula) Sample(size int, dim int) *mat.Dense {
 	// Hist(r, 50, "resources/"+arch.copula.Family()+".png")
 	return M
 }
+
+// sampleWith draws observations like Sample but with the given random
+// source (used by the parametric bootstrap to be reproducible)
+func (arch *ArchimedeanCopula) sampleWith(size int, dim int, rng *rand.Rand) *mat.Dense {
+	M := mat.NewDense(size, dim, nil)
+	Y := make([]float64, dim)
+	for i := 0; i < size; i++ {
+		for j := range Y {
+			Y[j] = rng.ExpFloat64()
+		}
+		Sd := scalarDiv(Y, sum(Y))
+		R := arch.RadialPpf(rng.Float64(), dim)
+		for R < 0. {
+			R = arch.RadialPpf(rng.Float64(), dim)
+		}
+		for j := 0; j < dim; j++ {
+			M.Set(i, j, arch.copula.Psi(R*Sd[j], arch.theta))
+		}
+	}
+	return M
+}
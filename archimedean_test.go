@@ -4,8 +4,11 @@ package gopula
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
+
+	"gonum.org/v1/gonum/mat"
 )
 
 var (
@@ -55,3 +58,100 @@ func TestPrintFitResults(t *testing.T) {
 	result := AC.Fit(M)
 	fmt.Println(result)
 }
+
+func TestStdErr(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTitle("Checking Wald bounds...")
+	AC := NewCopula("clayton", 1.)
+	result, err := AC.FitWithOptions(M, DefaultFitOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	down, up := result.WaldBounds(0.95)
+	// Wald and profile bounds are asymptotically equivalent
+	if math.Abs(down-result.LowerBound) > 0.01 || math.Abs(up-result.UpperBound) > 0.01 {
+		t.Errorf("Bad Wald bounds, expected [%.3f, %.3f], got [%.3f, %.3f]",
+			result.LowerBound, result.UpperBound, down, up)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestProfileUnbounded(t *testing.T) {
+	// very few dependent observations so that the upper bound goes beyond Inf
+	S := mat.NewDense(3, 2, []float64{
+		0.20, 0.25,
+		0.50, 0.45,
+		0.80, 0.85,
+	})
+
+	checkTitle("Checking unbounded profile bounds...")
+	AC := NewCopula("clayton", 1.)
+	result, err := AC.FitWithOptions(S, &FitOptions{Level: 0.99, Profile: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, up, errBounds := AC.ConfidenceBounds(S, 0.99)
+	if !result.UpperUnbounded || up != Inf || errBounds != ErrUnbounded {
+		t.Errorf("The upper bound should be unbounded (got %f)", result.UpperBound)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking default level...")
+	result, _ = AC.FitWithOptions(S, &FitOptions{Profile: true})
+	if result.Level != 0.95 || !(result.LowerBound < result.Theta) || !strings.Contains(result.String(), "95%") {
+		t.Errorf("The level should default to 0.95 (got %f)", result.Level)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking error message of Fit...")
+	// the density is infinite at the origin so that the optimizer fails
+	Z := mat.NewDense(2, 2, nil)
+	_, err = NewCopula("clayton", 1.).FitWithOptions(Z, DefaultFitOptions())
+	result = NewCopula("clayton", 1.).Fit(Z)
+	if err == nil || strings.Count(result.Message, err.Error()) != 1 {
+		t.Errorf("The message should report the error (%s)", result.Message)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking disabled profile bounds...")
+	result, _ = AC.FitWithOptions(S, &FitOptions{Level: 0.99, Profile: false})
+	if !math.IsNaN(result.UpperBound) || !math.IsNaN(result.LowerBound) {
+		t.Errorf("The profile bounds should not be computed")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestRadialCdf(t *testing.T) {
+	checkTitle("Checking radial cdf...")
+	AC := NewCopula("clayton", 2.)
+	x := 0.7
+	// F_R(x) = 1 - psi(x) + x psi'(x) in the bivariate case
+	expected := 1. - AC.Psi(x) + x*AC.PsiD(1, x)
+	if F := AC.RadialCdf(x, 2); math.Abs(F-expected) > 1e-12 {
+		t.Errorf("Bad radial cdf, expected %f, got %f", expected, F)
+		testERROR()
+		return
+	}
+	// F_R(x) = 1 - psi(x) + x psi'(x) - x² psi''(x) / 2 in dimension 3
+	expected -= x * x * AC.PsiD(2, x) / 2.
+	if F := AC.RadialCdf(x, 3); math.Abs(F-expected) > 1e-12 {
+		t.Errorf("Bad radial cdf, expected %f, got %f", expected, F)
+		testERROR()
+		return
+	}
+	testOK()
+}
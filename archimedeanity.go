@@ -0,0 +1,279 @@
+// archimedeanity.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ArchimedeanityGrid is the number of points per axis of the grids of
+// (0, 1)^3 and (0, 1)^2 where the associativity and the level sets
+// are checked
+var ArchimedeanityGrid = 10
+
+// processPoint gathers what the multiplier bootstrap needs
+// to evaluate the empirical copula process at a point
+type processPoint struct {
+	// indices of the observations lower than the point
+	lower []int
+	// number of observations lower than every coordinate
+	marginal [2]int
+	// partial derivatives of the empirical copula
+	partial [2]float64
+}
+
+// copulaProcess is the bivariate empirical copula of a sample
+// with the points where its multiplier process is evaluated
+type copulaProcess struct {
+	U      *mat.Dense
+	tree   *kdTree
+	h      float64
+	points map[[2]float64]*processPoint
+}
+
+// newCopulaProcess builds the empirical copula of the pseudo-observations U
+func newCopulaProcess(U *mat.Dense) *copulaProcess {
+	n, _ := U.Dims()
+	return &copulaProcess{
+		U:      U,
+		tree:   newKdTree(U),
+		h:      1. / math.Sqrt(float64(n)),
+		points: make(map[[2]float64]*processPoint),
+	}
+}
+
+// cdf computes the empirical copula at (u, v)
+func (cp *copulaProcess) cdf(u float64, v float64) float64 {
+	n, _ := cp.U.Dims()
+	return float64(cp.tree.countLower([]float64{u, v})) / float64(n)
+}
+
+// partial estimates the j-th partial derivative of the empirical copula
+// at x by finite differences (with a step 1/sqrt(n) shrunk at the
+// boundaries)
+func (cp *copulaProcess) partial(j int, x [2]float64) float64 {
+	lo, hi := x, x
+	lo[j] = math.Max(x[j]-cp.h, 0.)
+	hi[j] = math.Min(x[j]+cp.h, 1.)
+	return (cp.cdf(hi[0], hi[1]) - cp.cdf(lo[0], lo[1])) / (hi[j] - lo[j])
+}
+
+// point registers (u, v) as an evaluation point of the process
+func (cp *copulaProcess) point(u float64, v float64) *processPoint {
+	x := [2]float64{u, v}
+	if p, exists := cp.points[x]; exists {
+		return p
+	}
+	n, _ := cp.U.Dims()
+	p := &processPoint{lower: make([]int, 0)}
+	for i := 0; i < n; i++ {
+		row := cp.U.RawRowView(i)
+		if row[0] <= u {
+			p.marginal[0]++
+		}
+		if row[1] <= v {
+			p.marginal[1]++
+		}
+		if row[0] <= u && row[1] <= v {
+			p.lower = append(p.lower, i)
+		}
+	}
+	p.partial[0] = cp.partial(0, x)
+	p.partial[1] = cp.partial(1, x)
+	cp.points[x] = p
+	return p
+}
+
+// multiplier evaluates the multiplier replicate of the empirical copula
+// process (Rémillard and Scaillet, 2009) at a point, given the centered
+// multipliers xi and their cumulative sums along the ranks of every column
+func (p *processPoint) multiplier(xi []float64, cumulative [2][]float64) float64 {
+	joint := 0.
+	for _, i := range p.lower {
+		joint += xi[i]
+	}
+	return (joint -
+		p.partial[0]*cumulative[0][p.marginal[0]] -
+		p.partial[1]*cumulative[1][p.marginal[1]]) / math.Sqrt(float64(len(xi)))
+}
+
+// associativityTriple stores the points involved in the linearization of
+// C(C(u, v), w) - C(u, C(v, w)) at a triple (u, v, w)
+type associativityTriple struct {
+	uv, vw, left, right *processPoint
+	// empirical value of the associativity defect
+	defect float64
+}
+
+// ArchimedeanityResult details the output of the archimedeanity test
+type ArchimedeanityResult struct {
+	// Associativity is the test of C(C(u, v), w) = C(u, C(v, w))
+	Associativity *TestResult
+	// LevelSet is the test of the level-set property
+	LevelSet *TestResult
+	// PValue is the p-value of the combined test (Bonferroni: twice the
+	// smallest of the two p-values)
+	PValue float64
+}
+
+// ArchimedeanityTest tests whether the copula of a bivariate sample is
+// archimedean (Jaworski, 2010; Bücher, Dette and Volgushev, 2012) through
+// two of its characterizations, computed on a grid of the unit square
+// (see ArchimedeanityGrid):
+//   - associativity: by Ling's theorem, an archimedean copula satisfies
+//     C(C(u, v), w) = C(u, C(v, w)) (see associativityTest)
+//   - level sets: the level curves of an archimedean copula
+//     {phi(u) + phi(v) = phi(t)} are determined by the distribution of
+//     their levels (the Kendall distribution) so that C is the archimedean
+//     copula built from its own Kendall distribution (see levelSetTest)
+//
+// The hypothesis is rejected when one of the two tests rejects it.
+func ArchimedeanityTest(M *mat.Dense, opts *BootstrapOptions) (*ArchimedeanityResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if d != 2 {
+		return nil, fmt.Errorf("The test is only available for bivariate samples (got %d columns)", d)
+	}
+	if n < 3 {
+		return nil, fmt.Errorf("At least 3 observations are required (got %d)", n)
+	}
+	U := PseudoObservations(M)
+	m := ArchimedeanityGrid
+	grid := make([]float64, m)
+	for k := range grid {
+		grid[k] = float64(k+1) / float64(m+1)
+	}
+
+	associativity := associativityTest(U, grid, opts)
+	levelSet, err := levelSetTest(U, grid, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchimedeanityResult{
+		Associativity: associativity,
+		LevelSet:      levelSet,
+		PValue:        math.Min(1., 2.*math.Min(associativity.PValue, levelSet.PValue)),
+	}, nil
+}
+
+// associativityTest computes the Cramér-von Mises distance
+// n mean (C_n(C_n(u, v), w) - C_n(u, C_n(v, w)))² over the grid of
+// (0, 1)^3 of the pseudo-observations U. Its p-value is computed with the
+// multiplier bootstrap of the linearized associativity process (Bücher,
+// Dette and Volgushev, 2012).
+func associativityTest(U *mat.Dense, grid []float64, opts *BootstrapOptions) *TestResult {
+	n, _ := U.Dims()
+	m := len(grid)
+	cp := newCopulaProcess(U)
+	triples := make([]associativityTriple, 0, m*m*m)
+	statistic := 0.
+	for _, u := range grid {
+		for _, v := range grid {
+			for _, w := range grid {
+				cuv := cp.cdf(u, v)
+				cvw := cp.cdf(v, w)
+				t := associativityTriple{
+					uv:     cp.point(u, v),
+					vw:     cp.point(v, w),
+					left:   cp.point(cuv, w),
+					right:  cp.point(u, cvw),
+					defect: cp.cdf(cuv, w) - cp.cdf(u, cvw),
+				}
+				statistic += t.defect * t.defect
+				triples = append(triples, t)
+			}
+		}
+	}
+	statistic *= float64(n) / float64(len(triples))
+
+	// observations sorted along every column
+	var order [2][]int
+	for j := range order {
+		order[j] = make([]int, n)
+		for i := range order[j] {
+			order[j][i] = i
+		}
+		col := rawCol(U, j)
+		sort.Slice(order[j], func(a, b int) bool { return col[order[j][a]] < col[order[j][b]] })
+	}
+
+	return rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		xi := make([]float64, n)
+		for i := range xi {
+			xi[i] = rng.NormFloat64()
+		}
+		xiMean := mean(xi)
+		for i := range xi {
+			xi[i] -= xiMean
+		}
+		var cumulative [2][]float64
+		for j := range cumulative {
+			cumulative[j] = make([]float64, n+1)
+			for k, i := range order[j] {
+				cumulative[j][k+1] = cumulative[j][k] + xi[i]
+			}
+		}
+		s := 0.
+		for _, t := range triples {
+			a := t.left.multiplier(xi, cumulative) +
+				t.left.partial[0]*t.uv.multiplier(xi, cumulative) -
+				t.right.multiplier(xi, cumulative) -
+				t.right.partial[1]*t.vw.multiplier(xi, cumulative)
+			s += a * a
+		}
+		return s / float64(len(triples))
+	})
+}
+
+// levelSetStatistic computes the Cramér-von Mises distance
+// n mean (C_n(u, v) - C_K(u, v))² over the grid of (0, 1)^2 where C_K is
+// the archimedean copula whose Kendall distribution is the empirical one
+// of the pseudo-observations U (see NewEmpiricalArchimedean). It is
+// returned along with C_K.
+func levelSetStatistic(U *mat.Dense, grid []float64) (float64, *ArchimedeanCopula, error) {
+	n, _ := U.Dims()
+	ck, err := NewEmpiricalArchimedean(U)
+	if err != nil {
+		return math.NaN(), nil, err
+	}
+	ec := NewEmpiricalCopula(U)
+	s := 0.
+	x := make([]float64, 2)
+	for _, u := range grid {
+		for _, v := range grid {
+			x[0], x[1] = u, v
+			delta := ec.Cdf(x) - ck.Cdf(x)
+			s += delta * delta
+		}
+	}
+	return float64(n) * s / float64(len(grid)*len(grid)), ck, nil
+}
+
+// levelSetTest compares the empirical copula of the pseudo-observations U
+// with the archimedean copula having the same Kendall distribution
+// (see levelSetStatistic). Under the null hypothesis, the latter is a
+// consistent estimate of the copula so the p-value is computed by
+// drawing the replicates from it (the pseudo-observations of every
+// sample giving a new statistic).
+func levelSetTest(U *mat.Dense, grid []float64, opts *BootstrapOptions) (*TestResult, error) {
+	n, _ := U.Dims()
+	statistic, ck, err := levelSetStatistic(U, grid)
+	if err != nil {
+		return nil, err
+	}
+	return rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		s, _, err := levelSetStatistic(PseudoObservations(ck.sampleWith(n, 2, rng)), grid)
+		if err != nil {
+			// a degenerate replicate is counted as exceeding the statistic
+			return math.Inf(1)
+		}
+		return s
+	}), nil
+}
@@ -0,0 +1,77 @@
+// archimedeanity_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitArchimedeanity(t *testing.T) {
+	title("Archimedeanity")
+}
+
+func TestArchimedeanityTest(t *testing.T) {
+	M := NewCopula("clayton", 2.1).sampleWith(300, 2, rand.New(rand.NewSource(11)))
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 100
+
+	checkTitle("Checking archimedeanity test (clayton)...")
+	result, err := ArchimedeanityTest(M, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 || result.PValue != math.Min(1., 2.*math.Min(result.Associativity.PValue, result.LevelSet.PValue)) {
+		t.Errorf("The archimedeanity should not be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking archimedeanity test (shift)...")
+	X := independentSample(300, 2, 4)
+	for i := 0; i < 300; i++ {
+		v := X.At(i, 0) + 0.3 + 0.05*X.At(i, 1)
+		X.Set(i, 1, v-math.Floor(v))
+	}
+	result, err = ArchimedeanityTest(X, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Associativity.PValue > 0.05 || result.PValue > 0.1 {
+		t.Errorf("The associativity should be rejected (p = %f)", result.Associativity.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking archimedeanity test (level sets)...")
+	// mixture of the comonotone and the independence copulas: its level
+	// sets are not the ones of the archimedean copula with the same
+	// Kendall distribution
+	X = independentSample(300, 2, 5)
+	for i := 0; i < 150; i++ {
+		X.Set(i, 1, X.At(i, 0))
+	}
+	result, err = ArchimedeanityTest(X, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LevelSet.PValue > 0.05 || result.PValue > 0.1 {
+		t.Errorf("The level-set property should be rejected (p = %f)", result.LevelSet.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking archimedeanity test dimension...")
+	if _, err := ArchimedeanityTest(mat.NewDense(10, 3, nil), opts); err == nil {
+		t.Errorf("The test should fail in dimension 3")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
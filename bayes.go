@@ -0,0 +1,388 @@
+// bayes.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Prior is an interface to implement a prior distribution on theta
+type Prior interface {
+	LogDensity(theta float64) float64
+}
+
+// FlatPrior is the improper uniform prior on ThetaBounds
+type FlatPrior struct{}
+
+// LogDensity returns the (unnormalized) log-density of the prior
+func (p *FlatPrior) LogDensity(theta float64) float64 {
+	return 0.
+}
+
+// ThetaPrior is a prior directly set on theta
+type ThetaPrior struct {
+	// Dist is the prior distribution of theta
+	Dist distuv.LogProber
+}
+
+// LogDensity returns the log-density of the prior
+func (p *ThetaPrior) LogDensity(theta float64) float64 {
+	return p.Dist.LogProb(theta)
+}
+
+// TauPrior is a prior set on the Kendall's tau of the copula.
+// The density on theta includes the jacobian of the map theta -> tau.
+type TauPrior struct {
+	// Dist is the prior distribution of the Kendall's tau
+	Dist   distuv.LogProber
+	copula ArchimedeanCopuler
+}
+
+// NewTauPrior returns a prior on the Kendall's tau of the given copula
+func NewTauPrior(arch *ArchimedeanCopula, dist distuv.LogProber) *TauPrior {
+	return &TauPrior{Dist: dist, copula: arch.copula}
+}
+
+// LogDensity returns the log-density of the prior
+func (p *TauPrior) LogDensity(theta float64) float64 {
+	cop := &ArchimedeanCopula{theta: theta, copula: p.copula}
+	tau := cop.KendallTau()
+	h := 1e-5 * math.Max(1., math.Abs(theta))
+	cop.theta = theta + h
+	up := cop.KendallTau()
+	cop.theta = theta - h
+	down := cop.KendallTau()
+	return p.Dist.LogProb(tau) + math.Log(math.Abs(up-down)/(2.*h))
+}
+
+// Sampler is the name of a MCMC algorithm
+type Sampler string
+
+const (
+	// MetropolisSampler is the random-walk Metropolis algorithm
+	MetropolisSampler Sampler = "metropolis"
+	// SliceSampler is the univariate slice sampler (stepping-out procedure)
+	SliceSampler Sampler = "slice"
+)
+
+// MCMCOptions gathers the settings of the posterior sampling
+type MCMCOptions struct {
+	// Sampler is the MCMC algorithm
+	Sampler Sampler
+	// Chains is the number of chains (each one runs in its own goroutine)
+	Chains int
+	// Iterations is the number of kept iterations of every chain
+	Iterations int
+	// BurnIn is the number of discarded iterations at the beginning of every chain
+	BurnIn int
+	// Thin keeps one iteration out of Thin
+	Thin int
+	// Step is the standard deviation of the proposal (Metropolis) or the
+	// initial width of the slice. It is derived from the standard error
+	// of the maximum likelihood estimate when 0.
+	Step float64
+	// Seed makes the sampling reproducible
+	Seed int64
+}
+
+// DefaultMCMCOptions returns common MCMC settings
+func DefaultMCMCOptions() *MCMCOptions {
+	return &MCMCOptions{
+		Sampler:    MetropolisSampler,
+		Chains:     4,
+		Iterations: 1000,
+		BurnIn:     500,
+		Thin:       1,
+		Step:       0.,
+		Seed:       1,
+	}
+}
+
+// Posterior gathers the draws of the posterior distribution of theta
+type Posterior struct {
+	// Chains are the kept draws of every chain
+	Chains [][]float64
+	// AcceptanceRate is the acceptance rate of every chain
+	// (always 1 for the slice sampler)
+	AcceptanceRate []float64
+	// RHat is the Gelman-Rubin potential scale reduction factor
+	// (NaN with a single chain)
+	RHat float64
+	// ESS is the effective sample size of the pooled draws
+	ESS    float64
+	copula ArchimedeanCopuler
+}
+
+func (p *Posterior) String() string {
+	low, up := p.CredibleInterval(0.95)
+	format := "%8s %.6f\n%8s %.6f\n%8s [%.3f, %.3f]\n%8s %.4f\n%8s %.1f"
+	return fmt.Sprintf(format,
+		"Mean", p.Mean(),
+		"Std", stat.StdDev(p.Samples(), nil),
+		"95%", low, up,
+		"R-hat", p.RHat,
+		"ESS", p.ESS)
+}
+
+// Samples returns the pooled draws of all the chains
+func (p *Posterior) Samples() []float64 {
+	samples := make([]float64, 0)
+	for _, chain := range p.Chains {
+		samples = cat(samples, chain)
+	}
+	return samples
+}
+
+// Mean returns the posterior mean of theta
+func (p *Posterior) Mean() float64 {
+	return mean(p.Samples())
+}
+
+// Quantile returns the posterior quantile of theta
+func (p *Posterior) Quantile(q float64) float64 {
+	samples := p.Samples()
+	sort.Float64s(samples)
+	return stat.Quantile(q, stat.Empirical, samples, nil)
+}
+
+// CredibleInterval returns the equal-tailed credible interval of theta
+func (p *Posterior) CredibleInterval(level float64) (float64, float64) {
+	alpha := 0.5 * (1. - level)
+	return p.Quantile(alpha), p.Quantile(1. - alpha)
+}
+
+// PredictiveSample draws observations from the posterior predictive
+// distribution: every row is sampled from the copula whose parameter
+// is drawn from the posterior (the seed makes it reproducible)
+func (p *Posterior) PredictiveSample(size int, dim int, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	samples := p.Samples()
+	M := mat.NewDense(size, dim, nil)
+	for i := 0; i < size; i++ {
+		cop := &ArchimedeanCopula{theta: samples[rng.Intn(len(samples))], copula: p.copula}
+		M.SetRow(i, cop.sampleWith(1, dim, rng).RawRowView(0))
+	}
+	return M
+}
+
+// mcmcMaxTries is the maximum number of draws of the starting point of a
+// chain and of shrinkages of a slice
+const mcmcMaxTries = 100
+
+// FitBayesian samples the posterior distribution of theta given the
+// observations and the prior (the likelihood is given by LogLikelihood).
+// The chains are initialized around the maximum likelihood estimate and
+// run in parallel goroutines. The receiver is not modified. An error is
+// returned when a chain cannot start (the posterior is null around the
+// estimate) or when a slice cannot be sampled.
+func (arch *ArchimedeanCopula) FitBayesian(M *mat.Dense, prior Prior, opts *MCMCOptions) (*Posterior, error) {
+	if opts == nil {
+		opts = DefaultMCMCOptions()
+	}
+	if opts.Chains < 1 || opts.Iterations < 1 {
+		return nil, errors.New("At least one chain and one iteration are required")
+	}
+	if opts.Sampler != MetropolisSampler && opts.Sampler != SliceSampler {
+		return nil, fmt.Errorf("Unknown sampler '%s'", opts.Sampler)
+	}
+	thin := opts.Thin
+	if thin < 1 {
+		thin = 1
+	}
+
+	a, b := arch.copula.ThetaBounds()
+	logPosterior := func(theta float64) float64 {
+		if theta <= a || theta >= b {
+			return math.Inf(-1)
+		}
+		lp := prior.LogDensity(theta)
+		if math.IsNaN(lp) || math.IsInf(lp, -1) {
+			return math.Inf(-1)
+		}
+		return lp - arch.logLikelihoodToMinimize(theta, M)
+	}
+
+	// the maximum likelihood estimate gives the scale of the posterior
+	cop := &ArchimedeanCopula{theta: arch.theta, copula: arch.copula}
+	thetaHat, _, _, _, err := cop.maximizeLikelihood(M)
+	if err != nil {
+		return nil, err
+	}
+	cop.theta = thetaHat
+	scale := cop.StdErr(M)
+	if math.IsNaN(scale) {
+		scale = 0.01 * (b - a)
+	}
+	step := opts.Step
+	if step <= 0. {
+		step = 2.4 * scale
+	}
+
+	post := &Posterior{
+		Chains:         make([][]float64, opts.Chains),
+		AcceptanceRate: make([]float64, opts.Chains),
+		copula:         arch.copula,
+	}
+	errs := make([]error, opts.Chains)
+	parallelize(opts.Chains, opts.Chains, func(c int) {
+		rng := rand.New(rand.NewSource(opts.Seed + int64(c)))
+		// overdispersed starting point
+		theta := thetaHat + 2.*scale*rng.NormFloat64()
+		lp := logPosterior(theta)
+		for try := 0; !(lp > math.Inf(-1)) && try < mcmcMaxTries; try++ {
+			theta = thetaHat + scale*rng.NormFloat64()
+			lp = logPosterior(theta)
+		}
+		if !(lp > math.Inf(-1)) {
+			errs[c] = fmt.Errorf("The posterior is null around the estimate %f (chain %d)", thetaHat, c)
+			return
+		}
+
+		var next func(theta float64, lp float64) (float64, float64, bool, error)
+		switch opts.Sampler {
+		case SliceSampler:
+			next = func(theta float64, lp float64) (float64, float64, bool, error) {
+				theta, lp, err := sliceStep(logPosterior, theta, lp, step, rng)
+				return theta, lp, true, err
+			}
+		case MetropolisSampler:
+			next = func(theta float64, lp float64) (float64, float64, bool, error) {
+				theta, lp, ok := metropolisStep(logPosterior, theta, lp, step, rng)
+				return theta, lp, ok, nil
+			}
+		}
+
+		chain := make([]float64, 0, opts.Iterations)
+		accepted := 0
+		total := opts.BurnIn + opts.Iterations*thin
+		for it := 0; it < total; it++ {
+			var ok bool
+			var err error
+			theta, lp, ok, err = next(theta, lp)
+			if err != nil {
+				errs[c] = fmt.Errorf("%s (chain %d, iteration %d)", err.Error(), c, it)
+				return
+			}
+			if ok {
+				accepted++
+			}
+			if it >= opts.BurnIn && (it-opts.BurnIn)%thin == 0 {
+				chain = append(chain, theta)
+			}
+		}
+		post.Chains[c] = chain
+		post.AcceptanceRate[c] = float64(accepted) / float64(total)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	post.RHat = gelmanRubin(post.Chains)
+	post.ESS = effectiveSampleSize(post.Chains)
+	return post, nil
+}
+
+// metropolisStep performs one step of the random-walk Metropolis
+// algorithm from theta (whose log-density is lp). It returns the new
+// state, its log-density and whether the proposal has been accepted.
+func metropolisStep(logDensity func(float64) float64, theta float64, lp float64, step float64, rng *rand.Rand) (float64, float64, bool) {
+	proposal := theta + step*rng.NormFloat64()
+	lpProposal := logDensity(proposal)
+	if math.Log(rng.Float64()) < lpProposal-lp {
+		return proposal, lpProposal, true
+	}
+	return theta, lp, false
+}
+
+// sliceStep performs one step of the univariate slice sampler from theta
+// (whose log-density is lp) with the stepping-out and shrinkage procedures
+// (Neal, 2003). It returns the new state and its log-density. An error is
+// returned when no point of the slice is found after mcmcMaxTries shrinkages.
+func sliceStep(logDensity func(float64) float64, theta float64, lp float64, width float64, rng *rand.Rand) (float64, float64, error) {
+	maxSteps := 50
+	y := lp - rng.ExpFloat64()
+	left := theta - width*rng.Float64()
+	right := left + width
+	j := rng.Intn(maxSteps)
+	k := maxSteps - 1 - j
+	for ; j > 0 && logDensity(left) > y; j-- {
+		left -= width
+	}
+	for ; k > 0 && logDensity(right) > y; k-- {
+		right += width
+	}
+	for try := 0; try < mcmcMaxTries; try++ {
+		proposal := left + rng.Float64()*(right-left)
+		if lpProposal := logDensity(proposal); lpProposal > y {
+			return proposal, lpProposal, nil
+		}
+		if proposal < theta {
+			left = proposal
+		} else {
+			right = proposal
+		}
+	}
+	return theta, lp, errors.New("The slice collapsed without any valid point")
+}
+
+// gelmanRubin computes the potential scale reduction factor of the chains
+func gelmanRubin(chains [][]float64) float64 {
+	m := float64(len(chains))
+	if m < 2 {
+		return math.NaN()
+	}
+	n := float64(len(chains[0]))
+	means := make([]float64, len(chains))
+	W := 0.
+	for c, chain := range chains {
+		means[c] = mean(chain)
+		W += stat.Variance(chain, nil)
+	}
+	W /= m
+	B := n * stat.Variance(means, nil)
+	varPlus := (n-1.)/n*W + B/n
+	return math.Sqrt(varPlus / W)
+}
+
+// effectiveSampleSize computes the effective sample size of the pooled
+// chains from their mean autocorrelation (the sum is truncated when the
+// sum of two consecutive autocorrelations becomes negative)
+func effectiveSampleSize(chains [][]float64) float64 {
+	m := len(chains)
+	n := len(chains[0])
+	rho := func(lag int) float64 {
+		r := 0.
+		for _, chain := range chains {
+			mu, variance := stat.PopMeanVariance(chain, nil)
+			if variance == 0. {
+				continue
+			}
+			acov := 0.
+			for t := 0; t < n-lag; t++ {
+				acov += (chain[t] - mu) * (chain[t+lag] - mu)
+			}
+			r += acov / (float64(n) * variance)
+		}
+		return r / float64(m)
+	}
+
+	tau := 1.
+	for lag := 1; lag+1 < n; lag += 2 {
+		pair := rho(lag) + rho(lag+1)
+		if pair < 0. {
+			break
+		}
+		tau += 2. * pair
+	}
+	return float64(m*n) / tau
+}
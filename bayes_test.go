@@ -0,0 +1,106 @@
+// bayes_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestInitBayes(t *testing.T) {
+	title("Bayesian inference")
+}
+
+func TestFitBayesian(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	S := M.Slice(0, 300, 0, 3).(*mat.Dense)
+	AC := NewCopula("clayton", 1.)
+	result := AC.Fit(S)
+
+	for _, sampler := range []Sampler{MetropolisSampler, SliceSampler} {
+		checkTitle(fmt.Sprintf("Checking %s sampler...", sampler))
+		opts := &MCMCOptions{Sampler: sampler, Chains: 3, Iterations: 300, BurnIn: 100, Thin: 1, Seed: 3}
+		post, err := AC.FitBayesian(S, &FlatPrior{}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(post.Mean()-result.Theta) > 3.*result.StdErr {
+			t.Errorf("Bad posterior mean, expected about %f, got %f", result.Theta, post.Mean())
+			testERROR()
+			fmt.Println(post)
+		} else if post.RHat > 1.1 || post.ESS < 50. {
+			t.Errorf("Bad convergence diagnostics (R-hat = %f, ESS = %f)", post.RHat, post.ESS)
+			testERROR()
+			fmt.Println(post)
+		} else {
+			testOK()
+		}
+	}
+
+	checkTitle("Checking null posterior...")
+	// the prior excludes the fitted tau (about 0.5)
+	prior := NewTauPrior(AC, distuv.Uniform{Min: 0.9, Max: 0.95})
+	opts := &MCMCOptions{Sampler: SliceSampler, Chains: 2, Iterations: 10, Seed: 3}
+	if _, err := AC.FitBayesian(S, prior, opts); err == nil {
+		t.Errorf("The chains should not start")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking unknown sampler...")
+	opts = &MCMCOptions{Sampler: "slise", Chains: 2, Iterations: 10, Seed: 3}
+	if _, err := AC.FitBayesian(S, prior, opts); err == nil {
+		t.Errorf("The sampler 'slise' should be rejected")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking collapsed slice...")
+	nan := func(theta float64) float64 { return math.NaN() }
+	if _, _, err := sliceStep(nan, 1., 0., 1., rand.New(rand.NewSource(1))); err == nil {
+		t.Errorf("The slice should collapse")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestTauPrior(t *testing.T) {
+	checkTitle("Checking prior on Kendall's tau...")
+	AC := NewCopula("gumbel", 2.)
+	// with tau = 1 - 1/theta, a uniform prior on tau gives 1/theta²
+	prior := NewTauPrior(AC, distuv.Uniform{Min: 0., Max: 1.})
+	for _, theta := range []float64{1.5, 2., 5.} {
+		if math.Abs(prior.LogDensity(theta)+2.*math.Log(theta)) > 1e-6 {
+			t.Errorf("Bad prior log-density at %f, expected %f, got %f",
+				theta, -2.*math.Log(theta), prior.LogDensity(theta))
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestPredictiveSample(t *testing.T) {
+	checkTitle("Checking posterior predictive sampling...")
+	post := &Posterior{Chains: [][]float64{{2., 2.1, 2.2}}, copula: &Clayton{}}
+	P := post.PredictiveSample(20, 3, 1)
+	r, c := P.Dims()
+	if r != 20 || c != 3 || min(P.RawMatrix().Data) < 0. || max(P.RawMatrix().Data) > 1. ||
+		!mat.Equal(P, post.PredictiveSample(20, 3, 1)) {
+		t.Errorf("Bad predictive sample")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,284 @@
+// bootstrap.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Statistic is a quantity derived from a fitted copula.
+// Method expressions like (*ArchimedeanCopula).Theta or
+// (*ArchimedeanCopula).KendallTau can be used directly.
+type Statistic func(arch *ArchimedeanCopula) float64
+
+// BootstrapOptions gathers the settings of the resampling procedures
+type BootstrapOptions struct {
+	// Replicates is the number of bootstrap replicates
+	Replicates int
+	// Groups is the number of groups of the delete-a-group jackknife
+	// (delete-one jackknife when 0 or greater than the number of observations)
+	Groups int
+	// Level is the level of the confidence bounds (in (0, 1))
+	Level float64
+	// Seed makes the resampling reproducible
+	Seed int64
+	// Workers is the number of goroutines fitting the replicates
+	// (runtime.NumCPU() when 0)
+	Workers int
+}
+
+// DefaultBootstrapOptions returns common resampling settings
+func DefaultBootstrapOptions() *BootstrapOptions {
+	return &BootstrapOptions{
+		Replicates: 200,
+		Groups:     50,
+		Level:      0.95,
+		Seed:       1,
+		Workers:    0,
+	}
+}
+
+// ResamplingResult details the output of a bootstrap or
+// a jackknife procedure
+type ResamplingResult struct {
+	// Estimate is the statistic computed on the original sample
+	Estimate float64
+	// Replicates is the distribution of the statistic
+	// over the resamples (failed fits are removed)
+	Replicates []float64
+	// Bias is the estimated bias of the statistic
+	Bias float64
+	// StdErr is the estimated standard error of the statistic
+	StdErr float64
+	// Level is the level of the confidence bounds (in (0, 1))
+	Level float64
+	// LowerBound is the lower confidence bound (percentile method
+	// for the bootstrap, normal approximation for the jackknife)
+	LowerBound float64
+	// UpperBound is the upper confidence bound (percentile method
+	// for the bootstrap, normal approximation for the jackknife)
+	UpperBound float64
+	// BCaLowerBound is the lower bias-corrected and accelerated
+	// bootstrap bound (NaN for the jackknife)
+	BCaLowerBound float64
+	// BCaUpperBound is the upper bias-corrected and accelerated
+	// bootstrap bound (NaN for the jackknife)
+	BCaUpperBound float64
+}
+
+// ErrNoReplicate is returned when every resampled fit has failed
+var ErrNoReplicate = errors.New("No replicate has been successfully fitted")
+
+// fitStatistic fits a copy of the copula (the receiver is not modified)
+// on the given observations and returns the desired statistic
+func (arch *ArchimedeanCopula) fitStatistic(M *mat.Dense, statistic Statistic) float64 {
+	cop := &ArchimedeanCopula{theta: arch.theta, copula: arch.copula}
+	theta, _, _, _, err := cop.maximizeLikelihood(M)
+	if err != nil || math.IsNaN(theta) {
+		return math.NaN()
+	}
+	cop.theta = theta
+	return statistic(cop)
+}
+
+// selectRows builds the matrix made of the given rows of M
+func selectRows(M *mat.Dense, rows []int) *mat.Dense {
+	_, p := M.Dims()
+	S := mat.NewDense(len(rows), p, nil)
+	for i, r := range rows {
+		S.SetRow(i, M.RawRowView(r))
+	}
+	return S
+}
+
+// parallelize runs job(0), ..., job(n-1) with the given number of goroutines
+func parallelize(n int, workers int, job func(k int)) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				job(k)
+			}
+		}()
+	}
+	for k := 0; k < n; k++ {
+		jobs <- k
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// removeNaN returns the finite values of v
+func removeNaN(v []float64) []float64 {
+	output := make([]float64, 0, len(v))
+	for _, x := range v {
+		if !math.IsNaN(x) && !math.IsInf(x, 0) {
+			output = append(output, x)
+		}
+	}
+	return output
+}
+
+// Bootstrap computes nonparametric bootstrap confidence bounds (percentile
+// and BCa) of a statistic of the copula fitted on the observations.
+// Every replicate resamples the rows of M with its own random source
+// (seeded from opts.Seed) so that the result does not depend on the
+// scheduling of the goroutines. The receiver is not modified.
+func (arch *ArchimedeanCopula) Bootstrap(M *mat.Dense, statistic Statistic, opts *BootstrapOptions) (*ResamplingResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	if opts.Replicates < 1 {
+		return nil, fmt.Errorf("At least one replicate is required (got %d)", opts.Replicates)
+	}
+	if !(opts.Level > 0. && opts.Level < 1.) {
+		return nil, fmt.Errorf("The level must lie in (0, 1) (got %f)", opts.Level)
+	}
+	nObs, _ := M.Dims()
+	estimate := arch.fitStatistic(M, statistic)
+
+	replicates := make([]float64, opts.Replicates)
+	parallelize(opts.Replicates, opts.Workers, func(b int) {
+		rng := rand.New(rand.NewSource(opts.Seed + int64(b)))
+		rows := make([]int, nObs)
+		for i := range rows {
+			rows[i] = rng.Intn(nObs)
+		}
+		replicates[b] = arch.fitStatistic(selectRows(M, rows), statistic)
+	})
+	replicates = removeNaN(replicates)
+	if len(replicates) == 0 {
+		return nil, ErrNoReplicate
+	}
+
+	result := &ResamplingResult{
+		Estimate:   estimate,
+		Replicates: replicates,
+		Level:      opts.Level,
+	}
+	sorted := createCopy(replicates)
+	sort.Float64s(sorted)
+	mu, std := stat.MeanStdDev(sorted, nil)
+	result.Bias = mu - estimate
+	result.StdErr = std
+
+	alpha := 0.5 * (1. - opts.Level)
+	result.LowerBound = stat.Quantile(alpha, stat.Empirical, sorted, nil)
+	result.UpperBound = stat.Quantile(1.-alpha, stat.Empirical, sorted, nil)
+
+	// BCa bounds: the bias correction comes from the proportion of replicates
+	// below the estimate and the acceleration from the jackknife
+	below := 0.
+	for _, x := range sorted {
+		if x < estimate {
+			below++
+		}
+	}
+	z0 := distuv.UnitNormal.Quantile(below / float64(len(sorted)))
+	jack, err := arch.Jackknife(M, statistic, opts)
+	if err != nil {
+		return result, err
+	}
+	a := acceleration(jack.Replicates)
+	bca := func(p float64) float64 {
+		z := distuv.UnitNormal.Quantile(p)
+		q := distuv.UnitNormal.CDF(z0 + (z0+z)/(1.-a*(z0+z)))
+		if math.IsNaN(q) {
+			return math.NaN()
+		}
+		return stat.Quantile(q, stat.Empirical, sorted, nil)
+	}
+	result.BCaLowerBound = bca(alpha)
+	result.BCaUpperBound = bca(1. - alpha)
+	return result, nil
+}
+
+// acceleration computes the acceleration constant of the BCa
+// bounds from the jackknife replicates
+func acceleration(jack []float64) float64 {
+	m := mean(jack)
+	num := 0.
+	den := 0.
+	for _, x := range jack {
+		d := m - x
+		num += d * d * d
+		den += d * d
+	}
+	if den == 0. {
+		return 0.
+	}
+	return num / (6. * math.Pow(den, 1.5))
+}
+
+// Jackknife computes the (delete-a-group) jackknife estimates of the bias
+// and the standard error of a statistic of the copula fitted on the
+// observations, along with normal confidence bounds around the
+// bias-corrected estimate. The receiver is not modified.
+func (arch *ArchimedeanCopula) Jackknife(M *mat.Dense, statistic Statistic, opts *BootstrapOptions) (*ResamplingResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	if !(opts.Level > 0. && opts.Level < 1.) {
+		return nil, fmt.Errorf("The level must lie in (0, 1) (got %f)", opts.Level)
+	}
+	nObs, _ := M.Dims()
+	groups := opts.Groups
+	if groups <= 0 || groups > nObs {
+		groups = nObs
+	}
+	estimate := arch.fitStatistic(M, statistic)
+
+	// the observations are shuffled (reproducibly) before being grouped
+	perm := rand.New(rand.NewSource(opts.Seed)).Perm(nObs)
+	replicates := make([]float64, groups)
+	parallelize(groups, opts.Workers, func(g int) {
+		rows := make([]int, 0, nObs)
+		for i, r := range perm {
+			if i%groups != g {
+				rows = append(rows, r)
+			}
+		}
+		replicates[g] = arch.fitStatistic(selectRows(M, rows), statistic)
+	})
+	replicates = removeNaN(replicates)
+	if len(replicates) < 2 {
+		return nil, ErrNoReplicate
+	}
+
+	g := float64(len(replicates))
+	m := mean(replicates)
+	ss := 0.
+	for _, x := range replicates {
+		ss += (x - m) * (x - m)
+	}
+	result := &ResamplingResult{
+		Estimate:      estimate,
+		Replicates:    replicates,
+		Bias:          (g - 1.) * (m - estimate),
+		StdErr:        math.Sqrt((g - 1.) / g * ss),
+		Level:         opts.Level,
+		BCaLowerBound: math.NaN(),
+		BCaUpperBound: math.NaN(),
+	}
+	z := distuv.UnitNormal.Quantile(0.5 * (1. + opts.Level))
+	corrected := estimate - result.Bias
+	result.LowerBound = corrected - z*result.StdErr
+	result.UpperBound = corrected + z*result.StdErr
+	return result, nil
+}
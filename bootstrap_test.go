@@ -0,0 +1,80 @@
+// bootstrap_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestInitBootstrap(t *testing.T) {
+	title("Bootstrap")
+}
+
+func TestBootstrap(t *testing.T) {
+	S := NewCopula("clayton", 2.1).sampleWith(300, 3, rand.New(rand.NewSource(8)))
+	AC := NewCopula("clayton", 1.)
+	opts := &BootstrapOptions{Replicates: 40, Groups: 20, Level: 0.95, Seed: 7}
+
+	checkTitle("Checking bootstrap bounds...")
+	result, err := AC.Bootstrap(S, (*ArchimedeanCopula).Theta, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.LowerBound >= result.Estimate || result.UpperBound <= result.Estimate ||
+		result.BCaLowerBound >= result.Estimate || result.BCaUpperBound <= result.Estimate {
+		t.Errorf("The estimate %f should lie inside the bounds [%f, %f] (BCa [%f, %f])",
+			result.Estimate, result.LowerBound, result.UpperBound,
+			result.BCaLowerBound, result.BCaUpperBound)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking bootstrap reproducibility...")
+	again, err := AC.Bootstrap(S, (*ArchimedeanCopula).Theta, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for b := range result.Replicates {
+		if result.Replicates[b] != again.Replicates[b] {
+			t.Errorf("Replicates differ with the same seed (%f != %f)", result.Replicates[b], again.Replicates[b])
+			testERROR()
+			return
+		}
+	}
+	testOK()
+
+	checkTitle("Checking bootstrap options...")
+	_, err1 := AC.Bootstrap(S, (*ArchimedeanCopula).Theta, &BootstrapOptions{Replicates: -1, Level: 0.95})
+	_, err2 := AC.Bootstrap(S, (*ArchimedeanCopula).Theta, &BootstrapOptions{Replicates: 40, Level: 1.5})
+	_, err3 := AC.Jackknife(S, (*ArchimedeanCopula).Theta, &BootstrapOptions{Groups: 20, Level: 0.})
+	if err1 == nil || err2 == nil || err3 == nil {
+		t.Errorf("Bad replicates or levels should be rejected")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestJackknife(t *testing.T) {
+	S := NewCopula("clayton", 2.1).sampleWith(300, 3, rand.New(rand.NewSource(9)))
+	AC := NewCopula("clayton", 1.)
+	opts := &BootstrapOptions{Groups: 30, Level: 0.95, Seed: 7}
+
+	checkTitle("Checking jackknife on Kendall's tau...")
+	result, err := AC.Jackknife(S, (*ArchimedeanCopula).KendallTau, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tau := 2.10 / 4.10
+	if result.LowerBound > tau || result.UpperBound < tau || len(result.Replicates) != 30 {
+		t.Errorf("Bad jackknife bounds for Kendall's tau, expected %f inside [%f, %f]",
+			tau, result.LowerBound, result.UpperBound)
+		testERROR()
+		fmt.Println(result.Replicates)
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,149 @@
+// censoring.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Censoring status of a coordinate
+const (
+	// Observed means that the coordinate is exactly observed
+	Observed = 0
+	// RightCensored means that the true value is greater than the observed one
+	RightCensored = 1
+	// LeftCensored means that the true value is lower than the observed one
+	LeftCensored = 2
+	// IntervalCensored means that the true value lies between the observed
+	// one and the corresponding value of Upper
+	IntervalCensored = 3
+)
+
+// Censoring describes the censored coordinates of a batch of observations
+type Censoring struct {
+	// Status has the same shape as the observations and gives the
+	// censoring status of every coordinate (Observed, RightCensored,
+	// LeftCensored or IntervalCensored)
+	Status *mat.Dense
+	// Upper gives the upper bounds of the interval-censored coordinates
+	// (it may be nil when there is no such coordinate)
+	Upper *mat.Dense
+}
+
+// check ensures that the censoring matches the observations
+func (cens *Censoring) check(M *mat.Dense) error {
+	if cens.Status == nil {
+		return errors.New("The censoring status is missing")
+	}
+	n, d := M.Dims()
+	if r, c := cens.Status.Dims(); r != n || c != d {
+		return fmt.Errorf("The censoring status is %dx%d while the observations are %dx%d", r, c, n, d)
+	}
+	if cens.Upper == nil {
+		return nil
+	}
+	if r, c := cens.Upper.Dims(); r != n || c != d {
+		return fmt.Errorf("The upper bounds are %dx%d while the observations are %dx%d", r, c, n, d)
+	}
+	return nil
+}
+
+// censoredArgs gathers the arguments of the censored objective
+type censoredArgs struct {
+	M         *mat.Dense
+	censoring *Censoring
+}
+
+// partialCdf computes the mixed partial derivative of the cdf with respect
+// to the observed coordinates: psi^(k)(t) prod_j 1/psi'(phi(u_j)) where k is
+// the number of observed coordinates and t = sum_j phi(u_j)
+func (arch *ArchimedeanCopula) partialCdf(vector []float64, observed []bool, theta float64) float64 {
+	t := 0.
+	k := 0
+	for j, u := range vector {
+		t += arch.copula.PsiInv(u, theta)
+		if observed[j] {
+			k++
+		}
+	}
+	if k == 0 {
+		return arch.copula.Psi(t, theta)
+	}
+	p := arch.copula.PsiD(k, t, theta)
+	for j, u := range vector {
+		if observed[j] {
+			p /= arch.copula.PsiD(1, arch.copula.PsiInv(u, theta), theta)
+		}
+	}
+	return p
+}
+
+// censoredLogPdf computes the log-likelihood of a single observation
+// given the censoring status (and the upper bounds) of its coordinates.
+// The probability of the censored box is computed by inclusion-exclusion
+// over its corners (see Volume).
+func (arch *ArchimedeanCopula) censoredLogPdf(vector []float64, status []float64, upper []float64, theta float64) float64 {
+	d := len(vector)
+	observed := make([]bool, d)
+	lower := make([]float64, d)
+	higher := make([]float64, d)
+	censored := make([]int, 0, d)
+	for j, s := range status {
+		switch int(s) {
+		case RightCensored:
+			lower[j], higher[j] = vector[j], 1.
+		case LeftCensored:
+			lower[j], higher[j] = 0., vector[j]
+		case IntervalCensored:
+			if upper == nil {
+				return math.NaN()
+			}
+			lower[j], higher[j] = vector[j], upper[j]
+		default:
+			observed[j] = true
+			continue
+		}
+		censored = append(censored, j)
+	}
+	if len(censored) == 0 {
+		return arch.copula.LogPdf(vector, theta)
+	}
+
+	partial := func(corner []float64) float64 {
+		return arch.partialCdf(corner, observed, theta)
+	}
+	p := neumaierSum(cornerTerms(vector, lower, higher, censored, partial))
+	return math.Log(p)
+}
+
+// CensoredLogLikelihood computes the log-likelihood of a batch of
+// partially censored observations. The exactly observed coordinates
+// contribute through the derivatives of the cdf while the censored
+// ones contribute through the probability of their interval.
+func (arch *ArchimedeanCopula) CensoredLogLikelihood(M *mat.Dense, cens *Censoring) (float64, error) {
+	if err := cens.check(M); err != nil {
+		return math.NaN(), err
+	}
+	return -arch.censoredLogLikelihoodToMinimize(arch.theta, &censoredArgs{M: M, censoring: cens}), nil
+}
+
+func (arch *ArchimedeanCopula) censoredLogLikelihoodToMinimize(theta float64, args interface{}) float64 {
+	ca := args.(*censoredArgs)
+	nObs, _ := ca.M.Dims()
+	ll := 0.
+	for i := 0; i < nObs; i++ {
+		var upper []float64
+		if ca.censoring.Upper != nil {
+			upper = ca.censoring.Upper.RawRowView(i)
+		}
+		lpdf := arch.censoredLogPdf(ca.M.RawRowView(i), ca.censoring.Status.RawRowView(i), upper, theta)
+		if !math.IsNaN(lpdf) {
+			ll += lpdf
+		}
+	}
+	return -ll
+}
@@ -0,0 +1,127 @@
+// censoring_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitCensoring(t *testing.T) {
+	title("Censoring")
+}
+
+func TestCensoredLogLikelihood(t *testing.T) {
+	checkTitle("Checking uncensored likelihood...")
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	S := M.Slice(0, 200, 0, 3).(*mat.Dense)
+	AC := NewCopula("clayton", 2.)
+	cens := &Censoring{Status: mat.NewDense(200, 3, nil)}
+	ll, err := AC.CensoredLogLikelihood(S, cens)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := AC.LogLikelihood(S); math.Abs(ll-expected) > 1e-6*math.Abs(expected) {
+		t.Errorf("Bad likelihood, expected %f, got %f", expected, ll)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking left-censored likelihood...")
+	vector := []float64{0.3, 0.6, 0.8}
+	lpdf := AC.censoredLogPdf(vector, []float64{LeftCensored, LeftCensored, LeftCensored}, nil, 2.)
+	if expected := math.Log(AC.Cdf(vector)); math.Abs(lpdf-expected) > 1e-9 {
+		t.Errorf("Bad left-censored likelihood, expected %f, got %f", expected, lpdf)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking right-censored likelihood...")
+	// P(U_2 > 0) given U_1 = u is 1
+	lpdf = AC.censoredLogPdf([]float64{0.3, 1e-12}, []float64{Observed, RightCensored}, nil, 2.)
+	if math.Abs(lpdf) > 1e-6 {
+		t.Errorf("Bad right-censored likelihood, expected 0, got %f", lpdf)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking interval-censored likelihood...")
+	// the intervals (0, u] and (u, 1] partition the domain
+	status := []float64{Observed, IntervalCensored}
+	left := AC.censoredLogPdf([]float64{0.3, 1e-12}, status, []float64{0., 0.4}, 2.)
+	right := AC.censoredLogPdf([]float64{0.3, 0.4}, status, []float64{0., 1.}, 2.)
+	if s := math.Exp(left) + math.Exp(right); math.Abs(s-1.) > 1e-6 {
+		t.Errorf("Bad interval-censored likelihood, expected 1, got %f", s)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestCensoringDims(t *testing.T) {
+	checkTitle("Checking censoring dimensions...")
+	AC := NewCopula("clayton", 2.)
+	M := mat.NewDense(4, 2, []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8})
+	cens := &Censoring{Status: mat.NewDense(4, 3, nil)}
+	if _, err := AC.CensoredLogLikelihood(M, cens); err == nil {
+		t.Errorf("The censoring status should not match the observations")
+		testERROR()
+		return
+	}
+	if _, err := AC.FitWithOptions(M, &FitOptions{Censoring: cens}); err == nil {
+		t.Errorf("The censored fit should fail")
+		testERROR()
+		return
+	}
+	testOK()
+}
+
+func TestFitCensored(t *testing.T) {
+	checkTitle("Checking censored fit...")
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 1000
+	S := mat.DenseCopyOf(M.Slice(0, n, 0, 3))
+	// type I censoring: the values above 0.8 are only known to be greater
+	status := mat.NewDense(n, 3, nil)
+	censored := 0
+	for i := 0; i < n; i++ {
+		for j := 0; j < 3; j++ {
+			if S.At(i, j) > 0.8 {
+				S.Set(i, j, 0.8)
+				status.Set(i, j, RightCensored)
+				censored++
+			}
+		}
+	}
+
+	AC := NewCopula("clayton", 1.)
+	opts := DefaultFitOptions()
+	opts.Profile = false
+	opts.Censoring = &Censoring{Status: status}
+	result, err := AC.FitWithOptions(S, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if censored == 0 {
+		t.Errorf("No censored coordinate")
+		testERROR()
+	} else if math.Abs(result.Theta-2.10) > 0.25 || !(result.StdErr > 0.) {
+		t.Errorf("Bad censored fit, expected theta* = 2.10, got %f", result.Theta)
+		testERROR()
+		fmt.Println(result)
+	} else {
+		testOK()
+	}
+}
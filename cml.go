@@ -0,0 +1,270 @@
+// cml.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// thetaStep returns a finite-difference step for theta
+// keeping theta ± step inside ThetaBounds
+func (arch *ArchimedeanCopula) thetaStep() float64 {
+	a, b := arch.copula.ThetaBounds()
+	h := 1e-4 * math.Max(1., math.Abs(arch.theta))
+	return math.Min(h, 0.5*math.Min(arch.theta-a, b-arch.theta))
+}
+
+// scoreTheta computes the derivative of the log-density
+// with respect to theta (central difference with step h)
+func (arch *ArchimedeanCopula) scoreTheta(vector []float64, h float64) float64 {
+	return (arch.copula.LogPdf(vector, arch.theta+h) - arch.copula.LogPdf(vector, arch.theta-h)) / (2. * h)
+}
+
+// crossScore computes the derivative of scoreTheta with
+// respect to the j-th coordinate of the vector
+func (arch *ArchimedeanCopula) crossScore(vector []float64, j int, h float64) float64 {
+	u := vector[j]
+	delta := 1e-4 * math.Min(u, 1.-u)
+	v := createCopy(vector)
+	v[j] = u + delta
+	up := arch.scoreTheta(v, h)
+	v[j] = u - delta
+	down := arch.scoreTheta(v, h)
+	return (up - down) / (2. * delta)
+}
+
+// FitCML estimates theta through canonical maximum likelihood:
+// the copula is fitted on the pseudo-observations of X (its margins
+// are replaced by their empirical cdf). Besides the naïve bounds (the
+// pseudo-observations are considered as known uniforms), the result
+// holds the Genest-Ghoudi-Rivest sandwich standard error.
+func (arch *ArchimedeanCopula) FitCML(X *mat.Dense) (*FitResult, error) {
+	U := PseudoObservations(X)
+	result, err := arch.FitWithOptions(U, nil)
+	result.SandwichStdErr = arch.cmlStdErr(U)
+	return result, err
+}
+
+// cmlStdErr computes the Genest-Ghoudi-Rivest standard error of the
+// CML estimator. The score of every observation is corrected by the
+// terms W_j(U_ij) = 1/n sum_k 1{U_ij <= U_kj} d²/d𝜃du_j log c(U_k)
+// accounting for the empirical margins.
+func (arch *ArchimedeanCopula) cmlStdErr(U *mat.Dense) float64 {
+	n, d := U.Dims()
+	h := arch.thetaStep()
+	if !(h > 0.) {
+		return math.NaN()
+	}
+
+	z := make([]float64, n)
+	cross := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		row := U.RawRowView(i)
+		if s := arch.scoreTheta(row, h); !math.IsNaN(s) && !math.IsInf(s, 0) {
+			z[i] = s
+		}
+		for j := 0; j < d; j++ {
+			if g := arch.crossScore(row, j, h); !math.IsNaN(g) && !math.IsInf(g, 0) {
+				cross.Set(i, j, g)
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for j := 0; j < d; j++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool { return U.At(order[a], j) > U.At(order[b], j) })
+		// cumulative sums over the decreasing values of the column
+		// (ties share the same correction)
+		cum := 0.
+		for start := 0; start < n; {
+			end := start
+			for end < n && U.At(order[end], j) == U.At(order[start], j) {
+				cum += cross.At(order[end], j)
+				end++
+			}
+			for k := start; k < end; k++ {
+				z[order[k]] += cum / float64(n)
+			}
+			start = end
+		}
+	}
+
+	m := mean(z)
+	sigma2 := 0.
+	for _, x := range z {
+		sigma2 += (x - m) * (x - m)
+	}
+	sigma2 /= float64(n)
+
+	info := arch.ObservedInformation(U)
+	if !(info > 0.) {
+		return math.NaN()
+	}
+	return math.Sqrt(sigma2*float64(n)) / info
+}
+
+// FitIFM estimates theta through the inference functions for margins
+// method: every margin is first fitted by maximum likelihood, then the
+// copula is fitted on the transformed observations. Besides the naïve
+// bounds (the margins are considered as known), the result holds the
+// sandwich (Godambe) standard error of theta accounting for the
+// estimation of the margins. The margins are updated with their
+// estimated parameters.
+func (arch *ArchimedeanCopula) FitIFM(X *mat.Dense, margins []ParametricMargin) (*FitResult, error) {
+	_, d := X.Dims()
+	if len(margins) != d {
+		return nil, fmt.Errorf("%d margins are given while the observations have %d columns", len(margins), d)
+	}
+	generic := make([]Margin, d)
+	for j, m := range margins {
+		m.Fit(rawCol(X, j))
+		generic[j] = m
+	}
+	U, err := applyMargins(X, generic)
+	if err != nil {
+		return nil, err
+	}
+	result, err := arch.FitWithOptions(U, nil)
+	result.SandwichStdErr = arch.ifmStdErr(X, margins)
+	return result, err
+}
+
+// marginStep returns a finite-difference step for a margin parameter
+func marginStep(x float64) float64 {
+	return 1e-5 * math.Max(1., math.Abs(x))
+}
+
+// marginScores computes the derivatives of the log-density of the
+// margin with respect to its parameters for every observation
+// (n x k matrix where k is the number of parameters)
+func marginScores(m ParametricMargin, x []float64) *mat.Dense {
+	params := m.Params()
+	k := len(params)
+	S := mat.NewDense(len(x), k, nil)
+	tmp := createCopy(params)
+	for l := 0; l < k; l++ {
+		h := marginStep(params[l])
+		tmp[l] = params[l] + h
+		m.SetParams(tmp)
+		for i, xi := range x {
+			S.Set(i, l, m.LogProb(xi))
+		}
+		tmp[l] = params[l] - h
+		m.SetParams(tmp)
+		for i, xi := range x {
+			S.Set(i, l, (S.At(i, l)-m.LogProb(xi))/(2.*h))
+		}
+		tmp[l] = params[l]
+	}
+	m.SetParams(params)
+	return S
+}
+
+// ifmStdErr computes the sandwich standard error of the IFM estimator
+// of theta. The estimating functions are the margin scores stacked with
+// the copula score: V = D⁻¹ M D⁻ᵀ / n where D is the mean jacobian of the
+// estimating functions and M the mean of their outer products.
+func (arch *ArchimedeanCopula) ifmStdErr(X *mat.Dense, margins []ParametricMargin) float64 {
+	n, d := X.Dims()
+	nF := float64(n)
+	h := arch.thetaStep()
+	if !(h > 0.) {
+		return math.NaN()
+	}
+
+	// offsets of the parameters of every margin
+	offsets := make([]int, d+1)
+	for j, m := range margins {
+		offsets[j+1] = offsets[j] + len(m.Params())
+	}
+	p := offsets[d] + 1
+
+	U := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			U.Set(i, j, margins[j].CDF(X.At(i, j)))
+		}
+	}
+
+	// estimating functions of every observation
+	psi := mat.NewDense(n, p, nil)
+	D := mat.NewDense(p, p, nil)
+	for j, m := range margins {
+		x := rawCol(X, j)
+		S := marginScores(m, x)
+		psi.Slice(0, n, offsets[j], offsets[j+1]).(*mat.Dense).Copy(S)
+
+		params := m.Params()
+		tmp := createCopy(params)
+		for b := range params {
+			step := marginStep(params[b])
+
+			// jacobian of the margin scores
+			tmp[b] = params[b] + step
+			m.SetParams(tmp)
+			Sup := marginScores(m, x)
+			thetaUp := arch.shiftedScores(U, j, m, x, h)
+			tmp[b] = params[b] - step
+			m.SetParams(tmp)
+			Sdown := marginScores(m, x)
+			thetaDown := arch.shiftedScores(U, j, m, x, h)
+			tmp[b] = params[b]
+			m.SetParams(params)
+
+			for a := range params {
+				dsum := 0.
+				for i := 0; i < n; i++ {
+					dsum += Sup.At(i, a) - Sdown.At(i, a)
+				}
+				D.Set(offsets[j]+a, offsets[j]+b, dsum/(2.*step*nF))
+			}
+			// derivative of the copula score with respect to the margin parameter
+			D.Set(p-1, offsets[j]+b, (thetaUp-thetaDown)/(2.*step*nF))
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if s := arch.scoreTheta(U.RawRowView(i), h); !math.IsNaN(s) && !math.IsInf(s, 0) {
+			psi.Set(i, p-1, s)
+		}
+	}
+	info := arch.ObservedInformation(U)
+	if !(info > 0.) {
+		return math.NaN()
+	}
+	D.Set(p-1, p-1, -info/nF)
+
+	var Mpsi mat.Dense
+	Mpsi.Mul(psi.T(), psi)
+	Mpsi.Scale(1./nF, &Mpsi)
+
+	var Dinv mat.Dense
+	if err := Dinv.Inverse(D); err != nil {
+		return math.NaN()
+	}
+	var V mat.Dense
+	V.Product(&Dinv, &Mpsi, Dinv.T())
+	return math.Sqrt(V.At(p-1, p-1) / nF)
+}
+
+// shiftedScores computes the sum of the copula scores when the j-th
+// column of U is recomputed with the (perturbed) margin m
+func (arch *ArchimedeanCopula) shiftedScores(U *mat.Dense, j int, m Margin, x []float64, h float64) float64 {
+	n, _ := U.Dims()
+	s := 0.
+	for i := 0; i < n; i++ {
+		row := createCopy(U.RawRowView(i))
+		row[j] = m.CDF(x[i])
+		if si := arch.scoreTheta(row, h); !math.IsNaN(si) && !math.IsInf(si, 0) {
+			s += si
+		}
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+// cml_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitCML(t *testing.T) {
+	title("CML & IFM")
+}
+
+// loadClaytonObservations transforms the first rows of the clayton
+// sample with N(1, 2), Exp(3) and N(0, 1) quantile functions
+func loadClaytonObservations(t *testing.T, n int) *mat.Dense {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quantiles := []func(float64) float64{
+		NewNormalMargin(1., 2.).Quantile,
+		NewExponentialMargin(3.).Quantile,
+		NewNormalMargin(0., 1.).Quantile,
+	}
+	X := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < 3; j++ {
+			X.Set(i, j, quantiles[j](M.At(i, j)))
+		}
+	}
+	return X
+}
+
+func TestFitCML(t *testing.T) {
+	X := loadClaytonObservations(t, 1000)
+
+	checkTitle("Checking CML fit...")
+	AC := NewCopula("clayton", 1.)
+	result, err := AC.FitCML(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Theta-2.10) > 0.3 {
+		t.Errorf("Bad CML fit, expected theta* = 2.10, got %f", result.Theta)
+		testERROR()
+		fmt.Println(result)
+	} else if !(result.SandwichStdErr > result.StdErr) {
+		t.Errorf("The sandwich standard error (%f) should be greater than the naïve one (%f)",
+			result.SandwichStdErr, result.StdErr)
+		testERROR()
+		fmt.Println(result)
+	} else {
+		testOK()
+	}
+}
+
+func TestFitIFM(t *testing.T) {
+	X := loadClaytonObservations(t, 1000)
+
+	checkTitle("Checking IFM fit...")
+	AC := NewCopula("clayton", 1.)
+	margins := []ParametricMargin{
+		NewNormalMargin(0., 1.),
+		NewExponentialMargin(1.),
+		NewNormalMargin(0., 1.),
+	}
+	result, err := AC.FitIFM(X, margins)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate := margins[1].Params()[0]
+	if math.Abs(result.Theta-2.10) > 0.3 || math.Abs(rate-3.) > 0.3 {
+		t.Errorf("Bad IFM fit, expected (theta*, rate*) = (2.10, 3.0), got (%f, %f)", result.Theta, rate)
+		testERROR()
+		fmt.Println(result)
+	} else if !(result.SandwichStdErr > 0.) || result.SandwichStdErr > 10.*result.StdErr {
+		t.Errorf("Bad sandwich standard error (%f, naïve one is %f)",
+			result.SandwichStdErr, result.StdErr)
+		testERROR()
+		fmt.Println(result)
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,216 @@
+// comparison.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Correction is the penalty applied to the log-likelihood ratio
+// of two models to account for their number of parameters
+type Correction int
+
+const (
+	// NoCorrection compares the raw log-likelihoods
+	NoCorrection Correction = iota
+	// AICCorrection subtracts k1 - k2 (Akaike)
+	AICCorrection
+	// BICCorrection subtracts (k1 - k2) log(n) / 2 (Schwarz)
+	BICCorrection
+)
+
+// penalty returns the correction of the log-likelihood ratio of a model
+// with k1 parameters against a model with k2 parameters on n observations
+func (c Correction) penalty(k1 int, k2 int, n int) float64 {
+	switch c {
+	case AICCorrection:
+		return float64(k1 - k2)
+	case BICCorrection:
+		return float64(k1-k2) * math.Log(float64(n)) / 2.
+	default:
+		return 0.
+	}
+}
+
+// ComparisonResult details the output of a test comparing two models.
+// A positive statistic favors the first model.
+type ComparisonResult struct {
+	// Statistic is the Vuong z-statistic or the Clarke sign count
+	// (centered by n/2)
+	Statistic float64
+	// PValue is the two-sided p-value of the test (both models
+	// are equally close to the true distribution)
+	PValue float64
+	// LogLikelihoodRatio is the corrected log-likelihood ratio
+	LogLikelihoodRatio float64
+	// Observations is the number of observations used by the test
+	Observations int
+}
+
+// logDensities computes the log-density of the copula at every observation
+func logDensities(cop Parametric, M *mat.Dense) []float64 {
+	n, _ := M.Dims()
+	l := make([]float64, n)
+	for i := 0; i < n; i++ {
+		l[i] = cop.LogPdf(M.RawRowView(i))
+	}
+	return l
+}
+
+// logRatios computes the differences l1 - l2 of the log-density
+// contributions, skipping the observations where one of them is
+// not finite
+func logRatios(l1 []float64, l2 []float64) ([]float64, error) {
+	if len(l1) != len(l2) {
+		return nil, fmt.Errorf("The log-densities have different lengths (%d and %d)", len(l1), len(l2))
+	}
+	d := make([]float64, 0, len(l1))
+	for i := range l1 {
+		if math.IsNaN(l1[i]) || math.IsInf(l1[i], 0) || math.IsNaN(l2[i]) || math.IsInf(l2[i], 0) {
+			continue
+		}
+		d = append(d, l1[i]-l2[i])
+	}
+	if len(d) < 2 {
+		return nil, errors.New("At least 2 finite log-density contributions are required")
+	}
+	return d, nil
+}
+
+// VuongTest compares two non-nested models from their log-density
+// contributions l1 and l2 on the same observations (k1 and k2 are their
+// numbers of parameters). The statistic is the corrected log-likelihood
+// ratio divided by sqrt(n) times the standard deviation of the pointwise
+// ratios, which is asymptotically standard normal when both models are
+// equally close to the true distribution (Vuong, 1989).
+func VuongTest(l1 []float64, l2 []float64, k1 int, k2 int, correction Correction) (*ComparisonResult, error) {
+	d, err := logRatios(l1, l2)
+	if err != nil {
+		return nil, err
+	}
+	n := len(d)
+	lr := sum(d) - correction.penalty(k1, k2, n)
+	m := mean(d)
+	omega := 0.
+	for _, x := range d {
+		omega += (x - m) * (x - m)
+	}
+	omega = math.Sqrt(omega / float64(n))
+	if omega == 0. {
+		return nil, errors.New("The models have the same log-density contributions")
+	}
+	z := lr / (math.Sqrt(float64(n)) * omega)
+	return &ComparisonResult{
+		Statistic:          z,
+		PValue:             2. * distuv.UnitNormal.CDF(-math.Abs(z)),
+		LogLikelihoodRatio: lr,
+		Observations:       n,
+	}, nil
+}
+
+// ClarkeTest compares two non-nested models from their log-density
+// contributions l1 and l2 on the same observations (k1 and k2 are their
+// numbers of parameters). It counts the observations where the first
+// model is better, the correction being spread evenly over the
+// observations. The count follows a binomial distribution B(n, 1/2) when
+// both models are equally close to the true distribution (Clarke, 2007).
+// The ties are dropped.
+func ClarkeTest(l1 []float64, l2 []float64, k1 int, k2 int, correction Correction) (*ComparisonResult, error) {
+	d, err := logRatios(l1, l2)
+	if err != nil {
+		return nil, err
+	}
+	shift := correction.penalty(k1, k2, len(d)) / float64(len(d))
+	n, count := 0, 0
+	lr := 0.
+	for _, x := range d {
+		x -= shift
+		lr += x
+		if x > 0. {
+			count++
+		}
+		if x != 0. {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil, errors.New("The models have the same log-density contributions")
+	}
+	binomial := distuv.Binomial{N: float64(n), P: 0.5}
+	lowerTail := binomial.CDF(float64(count))
+	upperTail := 1.
+	if count > 0 {
+		upperTail = 1. - binomial.CDF(float64(count-1))
+	}
+	return &ComparisonResult{
+		Statistic:          float64(count) - float64(n)/2.,
+		PValue:             math.Min(1., 2.*math.Min(lowerTail, upperTail)),
+		LogLikelihoodRatio: lr,
+		Observations:       n,
+	}, nil
+}
+
+// ModelComparison gathers the pairwise comparisons of several models:
+// the (i, j) entries compare the i-th model against the j-th one
+type ModelComparison struct {
+	// Families are the families of the models
+	Families []string
+	// Vuong are the Vuong z-statistics
+	Vuong *mat.Dense
+	// VuongPValue are the p-values of the Vuong tests
+	VuongPValue *mat.Dense
+	// Clarke are the (centered) Clarke sign counts
+	Clarke *mat.Dense
+	// ClarkePValue are the p-values of the Clarke tests
+	ClarkePValue *mat.Dense
+}
+
+// CompareModels runs the Vuong and Clarke tests on every pair of fitted
+// models (see Fit or FitN) given the observations. The diagonal entries
+// have a null statistic and a p-value equal to 1.
+func CompareModels(M *mat.Dense, models []Parametric, correction Correction) (*ModelComparison, error) {
+	p := len(models)
+	if p < 2 {
+		return nil, fmt.Errorf("At least 2 models are required (got %d)", p)
+	}
+	mc := &ModelComparison{
+		Families:     make([]string, p),
+		Vuong:        mat.NewDense(p, p, nil),
+		VuongPValue:  mat.NewDense(p, p, nil),
+		Clarke:       mat.NewDense(p, p, nil),
+		ClarkePValue: mat.NewDense(p, p, nil),
+	}
+	l := make([][]float64, p)
+	for i, cop := range models {
+		mc.Families[i] = cop.Family()
+		l[i] = logDensities(cop, M)
+		mc.VuongPValue.Set(i, i, 1.)
+		mc.ClarkePValue.Set(i, i, 1.)
+	}
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			if i == j {
+				continue
+			}
+			ki, kj := len(models[i].Params()), len(models[j].Params())
+			vuong, err := VuongTest(l[i], l[j], ki, kj, correction)
+			if err != nil {
+				return nil, fmt.Errorf("%s vs %s: %v", mc.Families[i], mc.Families[j], err)
+			}
+			mc.Vuong.Set(i, j, vuong.Statistic)
+			mc.VuongPValue.Set(i, j, vuong.PValue)
+			clarke, err := ClarkeTest(l[i], l[j], ki, kj, correction)
+			if err != nil {
+				return nil, fmt.Errorf("%s vs %s: %v", mc.Families[i], mc.Families[j], err)
+			}
+			mc.Clarke.Set(i, j, clarke.Statistic)
+			mc.ClarkePValue.Set(i, j, clarke.PValue)
+		}
+	}
+	return mc, nil
+}
@@ -0,0 +1,153 @@
+// comparison_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitComparison(t *testing.T) {
+	title("Model comparison")
+}
+
+// fittedModels fits the clayton, gumbel and frank copulas
+// on the first rows of the clayton sample
+func fittedModels(t *testing.T) (*mat.Dense, []Parametric) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	M = mat.DenseCopyOf(M.Slice(0, 500, 0, 2))
+	models := make([]Parametric, 0)
+	for _, family := range []string{"clayton", "gumbel", "frank"} {
+		cop := NewCopula(family, 2.)
+		cop.Fit(M)
+		models = append(models, cop)
+	}
+	return M, models
+}
+
+func TestVuongTest(t *testing.T) {
+	M, models := fittedModels(t)
+	l1 := logDensities(models[0], M)
+	l2 := logDensities(models[1], M)
+
+	checkTitle("Checking Vuong test...")
+	result, err := VuongTest(l1, l2, 1, 1, NoCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Statistic < 0. || result.PValue > 0.05 {
+		t.Errorf("Clayton should be preferred (z = %f, p = %f)", result.Statistic, result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Vuong test antisymmetry...")
+	reverse, err := VuongTest(l2, l1, 1, 1, NoCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(reverse.Statistic+result.Statistic) > 1e-10 ||
+		math.Abs(reverse.PValue-result.PValue) > 1e-10 {
+		t.Errorf("Swapping the models should change the sign of the statistic (%f, %f)",
+			result.Statistic, reverse.Statistic)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Vuong test corrections...")
+	aic, _ := VuongTest(l1, l2, 2, 1, AICCorrection)
+	bic, _ := VuongTest(l1, l2, 2, 1, BICCorrection)
+	if math.Abs(aic.LogLikelihoodRatio-(result.LogLikelihoodRatio-1.)) > 1e-10 ||
+		math.Abs(bic.LogLikelihoodRatio-(result.LogLikelihoodRatio-math.Log(500.)/2.)) > 1e-10 {
+		t.Errorf("Bad corrections (AIC: %f, BIC: %f)", aic.LogLikelihoodRatio, bic.LogLikelihoodRatio)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Vuong test arguments...")
+	if _, err := VuongTest(l1, l2[1:], 1, 1, NoCorrection); err == nil {
+		t.Errorf("The contributions should have the same length")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestClarkeTest(t *testing.T) {
+	M, models := fittedModels(t)
+	l1 := logDensities(models[0], M)
+	l2 := logDensities(models[1], M)
+
+	checkTitle("Checking Clarke test...")
+	result, err := ClarkeTest(l1, l2, 1, 1, NoCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Statistic < 0. || result.PValue > 0.05 {
+		t.Errorf("Clayton should be preferred (B = %f, p = %f)", result.Statistic, result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Clarke test (balanced)...")
+	l := []float64{1., -1., 1., -1., 1., -1.}
+	zero := make([]float64, len(l))
+	result, err = ClarkeTest(l, zero, 1, 1, NoCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Statistic != 0. || result.PValue != 1. {
+		t.Errorf("Bad result (B = %f, p = %f)", result.Statistic, result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestCompareModels(t *testing.T) {
+	M, models := fittedModels(t)
+
+	checkTitle("Checking model comparison matrix...")
+	mc, err := CompareModels(M, models, BICCorrection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := true
+	for i := range models {
+		if mc.Vuong.At(i, i) != 0. || mc.VuongPValue.At(i, i) != 1. {
+			ok = false
+		}
+		for j := range models {
+			if math.Abs(mc.Vuong.At(i, j)+mc.Vuong.At(j, i)) > 1e-10 {
+				ok = false
+			}
+		}
+	}
+	// clayton beats the other families
+	if mc.Vuong.At(0, 1) <= 0. || mc.Vuong.At(0, 2) <= 0. {
+		ok = false
+	}
+	if !ok || mc.Families[0] != "Clayton" {
+		t.Errorf("Bad comparison matrix\n%v", mat.Formatted(mc.Vuong))
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking model comparison arguments...")
+	if _, err := CompareModels(M, models[:1], NoCorrection); err == nil {
+		t.Errorf("At least 2 models should be required")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
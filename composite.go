@@ -0,0 +1,136 @@
+// composite.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// columnPairs returns the pairs of columns used by the pairwise
+// likelihood: all the d(d-1)/2 pairs when size is 0 (or greater),
+// a reproducible random subset otherwise
+func columnPairs(d int, size int, seed int64) [][2]int {
+	pairs := make([][2]int, 0, d*(d-1)/2)
+	for j := 0; j < d; j++ {
+		for k := j + 1; k < d; k++ {
+			pairs = append(pairs, [2]int{j, k})
+		}
+	}
+	if size <= 0 || size >= len(pairs) {
+		return pairs
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(pairs), func(a, b int) { pairs[a], pairs[b] = pairs[b], pairs[a] })
+	return pairs[:size]
+}
+
+// pairwiseArgs gathers the arguments of the pairwise objective
+type pairwiseArgs struct {
+	M     *mat.Dense
+	pairs [][2]int
+}
+
+// pairwiseLogLikelihood computes the sum of the bivariate log-densities
+// of the given pairs of coordinates of a single observation
+func (arch *ArchimedeanCopula) pairwiseLogLikelihood(vector []float64, pairs [][2]int, theta float64) float64 {
+	ll := 0.
+	pair := make([]float64, 2)
+	for _, jk := range pairs {
+		pair[0], pair[1] = vector[jk[0]], vector[jk[1]]
+		lpdf := arch.copula.LogPdf(pair, theta)
+		if !math.IsNaN(lpdf) {
+			ll += lpdf
+		}
+	}
+	return ll
+}
+
+func (arch *ArchimedeanCopula) pairwiseLogLikelihoodToMinimize(theta float64, args interface{}) float64 {
+	pa := args.(*pairwiseArgs)
+	nObs, _ := pa.M.Dims()
+	ll := 0.
+	for i := 0; i < nObs; i++ {
+		ll += arch.pairwiseLogLikelihood(pa.M.RawRowView(i), pa.pairs, theta)
+	}
+	return -ll
+}
+
+// fitPairwise estimates theta by maximizing the pairwise composite
+// likelihood (sum of the bivariate log-densities over column pairs).
+// The composite likelihood is misspecified so the standard error comes
+// from the Godambe information H⁻¹ J H⁻¹ (both StdErr and SandwichStdErr)
+// where H is the opposite of the hessian and J the variance of the scores.
+// For the same reason, the composite likelihood ratio is asymptotically
+// (J/H) χ²(1) so the profile bounds are computed with the χ² quantile
+// scaled by J/H.
+func (arch *ArchimedeanCopula) fitPairwise(M *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	if opts.Censoring != nil {
+		return nil, fmt.Errorf("The fit method '%s' does not handle censoring", opts.Method)
+	}
+	_, d := M.Dims()
+	if d < 2 {
+		return nil, errors.New("The pairwise likelihood requires at least 2 columns")
+	}
+	args := &pairwiseArgs{M: M, pairs: columnPairs(d, opts.Pairs, opts.Seed)}
+
+	thetaBest, cllhood, feval, msg, err := arch.searchTheta(arch.pairwiseLogLikelihoodToMinimize, args, opts)
+	if err != nil {
+		msg += "Error: " + err.Error()
+	} else {
+		msg += "Success"
+	}
+	msg += fmt.Sprintf(" (pairwise likelihood over %d pairs)", len(args.pairs))
+	arch.theta = thetaBest
+
+	result := &FitResult{
+		Theta:          thetaBest,
+		LogLikelihood:  -cllhood,
+		StdErr:         math.NaN(),
+		SandwichStdErr: math.NaN(),
+		Level:          opts.Level,
+		UpperBound:     math.NaN(),
+		LowerBound:     math.NaN(),
+		Evals:          feval,
+		Message:        msg}
+
+	H := arch.observedInformation(arch.pairwiseLogLikelihoodToMinimize, args)
+	if !(H > 0.) {
+		return result, err
+	}
+
+	// variance of the scores of every observation
+	nObs, _ := M.Dims()
+	hs := arch.thetaStep()
+	J := 0.
+	for i := 0; i < nObs; i++ {
+		row := M.RawRowView(i)
+		s := (arch.pairwiseLogLikelihood(row, args.pairs, thetaBest+hs) -
+			arch.pairwiseLogLikelihood(row, args.pairs, thetaBest-hs)) / (2. * hs)
+		J += s * s
+	}
+	result.SandwichStdErr = math.Sqrt(J) / H
+	result.StdErr = result.SandwichStdErr
+
+	if opts.Profile && J > 0. {
+		// level of the likelihood ratio whose χ² quantile is the scaled one
+		cs := distuv.ChiSquared{K: 1}
+		level := cs.CDF(J / H * cs.Quantile(opts.Level))
+		down, downUnbounded, errDown := arch.profileBound(arch.pairwiseLogLikelihoodToMinimize, args, level, false)
+		up, upUnbounded, errUp := arch.profileBound(arch.pairwiseLogLikelihoodToMinimize, args, level, true)
+		result.LowerBound, result.LowerUnbounded = down, downUnbounded
+		result.UpperBound, result.UpperUnbounded = up, upUnbounded
+		if err == nil && errDown != nil {
+			err = errDown
+		}
+		if err == nil && errUp != nil {
+			err = errUp
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,89 @@
+// composite_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitComposite(t *testing.T) {
+	title("Composite likelihood")
+}
+
+func TestColumnPairs(t *testing.T) {
+	checkTitle("Checking column pairs...")
+	all := columnPairs(6, 0, 1)
+	some := columnPairs(6, 4, 1)
+	if len(all) != 15 || len(some) != 4 {
+		t.Errorf("Bad number of pairs, expected (15, 4), got (%d, %d)", len(all), len(some))
+		testERROR()
+		return
+	}
+	for _, jk := range some {
+		if jk[0] >= jk[1] || jk[1] >= 6 {
+			t.Errorf("Bad pair %v", jk)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestFitPairwise(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	S := M.Slice(0, 2000, 0, 3).(*mat.Dense)
+
+	for _, pairs := range []int{0, 2} {
+		checkTitle(fmt.Sprintf("Checking pairwise fit (%d pairs)...", pairs))
+		AC := NewCopula("clayton", 1.)
+		opts := &FitOptions{Method: PairwiseLikelihood, Level: 0.95, Pairs: pairs, Seed: 1}
+		result, err := AC.FitWithOptions(S, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.Theta-2.10) > 0.2 {
+			t.Errorf("Bad pairwise fit, expected theta* = 2.10, got %f", result.Theta)
+			testERROR()
+			fmt.Println(result)
+		} else if !(result.SandwichStdErr > 0.) || result.SandwichStdErr > 0.5 {
+			t.Errorf("Bad Godambe standard error (%f)", result.SandwichStdErr)
+			testERROR()
+			fmt.Println(result)
+		} else {
+			testOK()
+		}
+	}
+
+	checkTitle("Checking pairwise profile bounds...")
+	AC := NewCopula("clayton", 1.)
+	opts := &FitOptions{Method: PairwiseLikelihood, Level: 0.95, Profile: true}
+	result, err := AC.FitWithOptions(S, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the adjusted profile bounds are close to the Godambe ones
+	down, up := result.SandwichBounds(0.95)
+	if result.StdErr != result.SandwichStdErr ||
+		math.Abs(result.LowerBound-down) > 0.2*(up-down) || math.Abs(result.UpperBound-up) > 0.2*(up-down) {
+		t.Errorf("Bad profile bounds [%f, %f] (Godambe [%f, %f])", result.LowerBound, result.UpperBound, down, up)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking pairwise censoring...")
+	opts.Censoring = &Censoring{Status: mat.NewDense(2000, 3, nil)}
+	if _, err := AC.FitWithOptions(S, opts); err == nil {
+		t.Errorf("The pairwise likelihood should not handle censoring")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,72 @@
+// dependence.go
+
+package gopula
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+var (
+	// QuadPoints is the number of Gauss-Legendre nodes used
+	// to integrate the generator
+	QuadPoints = 200
+)
+
+// KendallTau computes the Kendall's tau of the bivariate margins
+// of the copula through the generator:
+// 𝜏 = 1 + 4 ∫ PsiInv(t) / PsiInv'(t) dt (on [0, 1])
+func (arch *ArchimedeanCopula) KendallTau() float64 {
+	switch arch.Family() {
+	case "Clayton":
+		return arch.theta / (arch.theta + 2.)
+	case "Gumbel":
+		return 1. - 1./arch.theta
+	}
+	integrand := func(t float64) float64 {
+		phi := arch.copula.PsiInv(t, arch.theta)
+		if phi <= 0. {
+			// numerical underflow close to t = 1 where
+			// the integrand vanishes
+			return 0.
+		}
+		// PsiInv'(t) = 1 / Psi'(PsiInv(t))
+		return phi * arch.copula.PsiD(1, phi, arch.theta)
+	}
+	return 1. + 4.*quad.Fixed(integrand, 0., 1., QuadPoints, nil, 0)
+}
+
+// LowerTailDependence computes the lower tail dependence coefficient
+// of the bivariate margins of the copula:
+// 𝜆 = lim C(u,u)/u when u -> 0 = lim 2 Psi'(2t)/Psi'(t) when t -> ∞
+func (arch *ArchimedeanCopula) LowerTailDependence() float64 {
+	switch arch.Family() {
+	case "Clayton":
+		return math.Pow(2., -1./arch.theta)
+	case "AMH":
+		if arch.theta == 1. {
+			return 0.5
+		}
+		return 0.
+	case "Frank", "Gumbel", "Joe":
+		return 0.
+	}
+	t := 1e8
+	return 2. * arch.copula.PsiD(1, 2.*t, arch.theta) / arch.copula.PsiD(1, t, arch.theta)
+}
+
+// UpperTailDependence computes the upper tail dependence coefficient
+// of the bivariate margins of the copula:
+// 𝜆 = lim (1 - 2u + C(u,u))/(1 - u) when u -> 1
+// = 2 - lim 2 Psi'(2t)/Psi'(t) when t -> 0
+func (arch *ArchimedeanCopula) UpperTailDependence() float64 {
+	switch arch.Family() {
+	case "Gumbel", "Joe":
+		return 2. - math.Pow(2., 1./arch.theta)
+	case "AMH", "Clayton", "Frank":
+		return 0.
+	}
+	t := 1e-8
+	return 2. - 2.*arch.copula.PsiD(1, 2.*t, arch.theta)/arch.copula.PsiD(1, t, arch.theta)
+}
@@ -0,0 +1,55 @@
+// dependence_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInitDependence(t *testing.T) {
+	title("Dependence measures")
+}
+
+func TestKendallTau(t *testing.T) {
+	checkTitle("Checking Kendall's tau...")
+	// reference values from the closed-form expressions (series for Joe)
+	expected := map[string]float64{
+		"amh":   0.1287648,
+		"frank": 0.3072470,
+		"joe":   0.5179625,
+	}
+	theta := map[string]float64{
+		"amh":   0.5,
+		"frank": 3.,
+		"joe":   3.,
+	}
+	for family, tau := range expected {
+		AC := NewCopula(family, theta[family])
+		if math.Abs(AC.KendallTau()-tau) > 1e-6 {
+			t.Errorf("Bad Kendall's tau for %s copula, expected %f, got %f", family, tau, AC.KendallTau())
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestTailDependence(t *testing.T) {
+	checkTitle("Checking tail dependence...")
+	AC := NewCopula("clayton", 2.)
+	if math.Abs(AC.LowerTailDependence()-math.Sqrt(0.5)) > 1e-10 || AC.UpperTailDependence() != 0. {
+		t.Errorf("Bad tail dependence, expected (0.707, 0), got (%f, %f)",
+			AC.LowerTailDependence(), AC.UpperTailDependence())
+		testERROR()
+		return
+	}
+	AC = NewCopula("gumbel", 2.)
+	if math.Abs(AC.UpperTailDependence()-(2.-math.Sqrt(2.))) > 1e-10 || AC.LowerTailDependence() != 0. {
+		t.Errorf("Bad tail dependence, expected (0, 0.586), got (%f, %f)",
+			AC.LowerTailDependence(), AC.UpperTailDependence())
+		testERROR()
+		return
+	}
+	testOK()
+}
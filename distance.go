@@ -0,0 +1,81 @@
+// distance.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// distanceArgs gathers the points where the empirical copula is
+// compared to the copula and the values of the empirical copula there
+type distanceArgs struct {
+	points *mat.Dense
+	target []float64
+}
+
+// distanceToMinimize computes the mean of (C_n(u) - C(u))² over the points
+func (arch *ArchimedeanCopula) distanceToMinimize(theta float64, args interface{}) float64 {
+	a := args.(*distanceArgs)
+	s := 0.
+	for k, c := range a.target {
+		delta := c - arch.copula.Cdf(a.points.RawRowView(k), theta)
+		if !math.IsNaN(delta) {
+			s += delta * delta
+		}
+	}
+	return s / float64(len(a.target))
+}
+
+// newDistanceArgs returns the arguments of the distance between the
+// empirical copula of M and the copula, either integrated with respect
+// to the empirical copula (Cramér-von Mises, at the pseudo-observations)
+// or with respect to the Lebesgue measure (L2, at quasi-Monte Carlo nodes)
+func newDistanceArgs(M *mat.Dense, method FitMethod) *distanceArgs {
+	ec := NewEmpiricalCopula(M)
+	_, d := M.Dims()
+	points := ec.U
+	if method == MinimumL2 {
+		points = haltonPoints(QMCPoints, d)
+	}
+	k, _ := points.Dims()
+	target := make([]float64, k)
+	for i := range target {
+		target[i] = ec.Cdf(points.RawRowView(i))
+	}
+	return &distanceArgs{points: points, target: target}
+}
+
+// fitDistance estimates theta by minimizing a distance between the
+// empirical copula of the observations and the copula (Tsukahara, 2005;
+// Weiß, 2011). Only the Cdf of the copula is involved so that it remains
+// stable when the density is not (Joe or Gumbel in high dimension). The
+// standard error is not available (see Bootstrap).
+func (arch *ArchimedeanCopula) fitDistance(M *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	if opts.Censoring != nil {
+		return nil, fmt.Errorf("The fit method '%s' does not handle censoring", opts.Method)
+	}
+	args := newDistanceArgs(M, opts.Method)
+
+	thetaBest, distance, feval, msg, err := arch.searchTheta(arch.distanceToMinimize, args, opts)
+	if err != nil {
+		msg += "Error: " + err.Error()
+	} else {
+		msg += "Success"
+	}
+	msg += fmt.Sprintf(" (minimum %s distance %.3e)", opts.Method, distance)
+	arch.theta = thetaBest
+
+	return &FitResult{
+		Theta:          thetaBest,
+		LogLikelihood:  arch.LogLikelihood(M),
+		StdErr:         math.NaN(),
+		SandwichStdErr: math.NaN(),
+		Level:          opts.Level,
+		UpperBound:     math.NaN(),
+		LowerBound:     math.NaN(),
+		Evals:          feval,
+		Message:        msg}, err
+}
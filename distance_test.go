@@ -0,0 +1,70 @@
+// distance_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestInitDistance(t *testing.T) {
+	title("Minimum distance estimation")
+}
+
+func TestFitMinimumDistance(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AC := NewCopula("clayton", 1.)
+	opts := DefaultFitOptions()
+
+	for _, method := range []FitMethod{MinimumCvM, MinimumL2} {
+		checkTitle("Checking minimum " + string(method) + " distance (clayton)...")
+		opts.Method = method
+		result, err := AC.FitWithOptions(M, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.Theta-2.1) > 0.2 || !math.IsNaN(result.StdErr) {
+			t.Errorf("Bad estimate (%f instead of 2.1)", result.Theta)
+			testERROR()
+		} else {
+			testOK()
+		}
+	}
+}
+
+func TestFitMinimumDistanceHighDimension(t *testing.T) {
+	for _, family := range []string{"gumbel", "joe"} {
+		checkTitle("Checking minimum cvm distance (" + family + " in dimension 6)...")
+		AC := NewCopula(family, 3.)
+		M := AC.sampleWith(400, 6, rand.New(rand.NewSource(5)))
+		opts := DefaultFitOptions()
+		opts.Method = MinimumCvM
+		fitted := NewCopula(family, 1.5)
+		result, err := fitted.FitWithOptions(M, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.Theta-3.) > 0.3 {
+			t.Errorf("Bad estimate (%f instead of 3)", result.Theta)
+			testERROR()
+		} else {
+			testOK()
+		}
+	}
+
+	checkTitle("Checking minimum distance with censoring...")
+	AC := NewCopula("clayton", 1.)
+	opts := DefaultFitOptions()
+	opts.Method = MinimumL2
+	opts.Censoring = &Censoring{}
+	if _, err := AC.FitWithOptions(AC.Sample(10, 2), opts); err == nil {
+		t.Errorf("The censoring should not be handled")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
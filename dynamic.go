@@ -0,0 +1,278 @@
+// dynamic.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Dynamics is the name of a recursion driving the parameter
+// of a dynamic copula
+type Dynamics string
+
+const (
+	// ScoreDriven updates the transformed parameter with the score of the
+	// last observation: f_(t+1) = omega + A s_t + B f_t where s_t is the
+	// derivative of log c(u_t) with respect to f_t (generalized
+	// autoregressive score of Creal, Koopman and Lucas, 2013, with a
+	// unit scaling)
+	ScoreDriven Dynamics = "gas"
+	// PattonDynamics updates the transformed parameter with the mean
+	// absolute difference between the coordinates of the last observations:
+	// f_(t+1) = omega + A x_t + B f_t where x_t is the mean of |u_i - u_j|
+	// over the last PattonWindow observations (Patton, 2006)
+	PattonDynamics Dynamics = "patton"
+)
+
+// PattonWindow is the number of past observations averaged
+// by the forcing variable of the Patton dynamics
+var PattonWindow = 10
+
+// DynamicCopula is an archimedean copula whose parameter changes over
+// time. The recursion is performed on the transformed parameter
+// f_t = logit((theta_t - a) / (b - a)) where [a, b] are the ThetaBounds
+// of the family so that theta_t always remains in the domain.
+type DynamicCopula struct {
+	// Copula gives the family of the copula (its parameter is ignored)
+	Copula *ArchimedeanCopula
+	// Dynamics is the recursion driving the parameter
+	Dynamics Dynamics
+	// Omega is the intercept of the recursion
+	Omega float64
+	// A is the coefficient of the score or of the forcing variable
+	A float64
+	// B is the autoregressive coefficient (|B| < 1)
+	B float64
+	// F0 is the transformed parameter of the first observation
+	F0 float64
+}
+
+// NewDynamicCopula returns a dynamic copula of the family of the given
+// copula. The recursion starts from the parameter of the copula and
+// does not move until the model is fitted.
+func NewDynamicCopula(copula *ArchimedeanCopula, dynamics Dynamics) *DynamicCopula {
+	dc := &DynamicCopula{Copula: copula, Dynamics: dynamics}
+	dc.F0 = dc.transform(copula.Theta())
+	dc.Omega = dc.F0
+	return dc
+}
+
+// theta maps a transformed parameter to the domain of the family
+func (dc *DynamicCopula) theta(f float64) float64 {
+	a, b := dc.Copula.copula.ThetaBounds()
+	return toBounded(f, a, b)
+}
+
+// transform maps a parameter of the family to the real line
+func (dc *DynamicCopula) transform(theta float64) float64 {
+	a, b := dc.Copula.copula.ThetaBounds()
+	// the parameter is slightly moved inside the domain
+	margin := 1e-6 * (b - a)
+	return toUnbounded(math.Max(a+margin, math.Min(b-margin, theta)), a, b)
+}
+
+// logPdf computes the log-density of the family at the given
+// transformed parameter
+func (dc *DynamicCopula) logPdf(vector []float64, f float64) float64 {
+	return dc.Copula.copula.LogPdf(vector, dc.theta(f))
+}
+
+// score computes the derivative of the log-density with respect to the
+// transformed parameter (0 when it is not defined)
+func (dc *DynamicCopula) score(vector []float64, f float64) float64 {
+	const h = 1e-4
+	s := (dc.logPdf(vector, f+h) - dc.logPdf(vector, f-h)) / (2. * h)
+	if math.IsNaN(s) || math.IsInf(s, 0) {
+		return 0.
+	}
+	return s
+}
+
+// forcing computes the mean absolute difference between the coordinates
+// of the observations t, t-1, ..., t-PattonWindow+1 (the available ones)
+func forcing(M *mat.Dense, t int) float64 {
+	_, d := M.Dims()
+	s := 0.
+	count := 0
+	for k := t; k >= 0 && k > t-PattonWindow; k-- {
+		row := M.RawRowView(k)
+		for i := 0; i < d; i++ {
+			for j := i + 1; j < d; j++ {
+				s += math.Abs(row[i] - row[j])
+				count++
+			}
+		}
+	}
+	return s / float64(count)
+}
+
+// next computes the transformed parameter following the t-th observation
+func (dc *DynamicCopula) next(M *mat.Dense, t int, f float64) float64 {
+	if dc.Dynamics == PattonDynamics {
+		return dc.Omega + dc.A*forcing(M, t) + dc.B*f
+	}
+	return dc.Omega + dc.A*dc.score(M.RawRowView(t), f) + dc.B*f
+}
+
+// path computes the transformed parameters of the observations and the
+// one following the last observation (n+1 values) along with the
+// log-likelihood (NaN contributions are skipped)
+func (dc *DynamicCopula) path(M *mat.Dense) ([]float64, float64) {
+	n, _ := M.Dims()
+	f := make([]float64, n+1)
+	f[0] = dc.F0
+	ll := 0.
+	for t := 0; t < n; t++ {
+		if l := dc.logPdf(M.RawRowView(t), f[t]); !math.IsNaN(l) {
+			ll += l
+		}
+		f[t+1] = dc.next(M, t, f[t])
+	}
+	return f, ll
+}
+
+// LogLikelihood computes the log-likelihood of the time-ordered observations
+func (dc *DynamicCopula) LogLikelihood(M *mat.Dense) float64 {
+	_, ll := dc.path(M)
+	return ll
+}
+
+// Filter computes the parameter theta_t of every time-ordered observation
+func (dc *DynamicCopula) Filter(M *mat.Dense) []float64 {
+	f, _ := dc.path(M)
+	theta := make([]float64, len(f)-1)
+	for t := range theta {
+		theta[t] = dc.theta(f[t])
+	}
+	return theta
+}
+
+// Forecast computes the parameters of the horizon observations following
+// the time-ordered observations. Beyond the first step, the score is
+// replaced by its expectation (0) and the forcing variable of the Patton
+// dynamics by its mean over the observations.
+func (dc *DynamicCopula) Forecast(M *mat.Dense, horizon int) []float64 {
+	n, _ := M.Dims()
+	f, _ := dc.path(M)
+	x := 0.
+	if dc.Dynamics == PattonDynamics {
+		for t := 0; t < n; t++ {
+			x += forcing(M, t)
+		}
+		x /= float64(n)
+	}
+	theta := make([]float64, horizon)
+	ft := f[n]
+	for h := 0; h < horizon; h++ {
+		theta[h] = dc.theta(ft)
+		ft = dc.Omega + dc.A*x + dc.B*ft
+	}
+	return theta
+}
+
+// Params returns the parameters [Omega, A, B] of the recursion
+func (dc *DynamicCopula) Params() []float64 {
+	return []float64{dc.Omega, dc.A, dc.B}
+}
+
+// SetParams updates the parameters [Omega, A, B] of the recursion
+func (dc *DynamicCopula) SetParams(params []float64) error {
+	if len(params) != 3 {
+		return fmt.Errorf("The dynamics has 3 parameters (got %d)", len(params))
+	}
+	if math.Abs(params[2]) >= 1. {
+		return fmt.Errorf("The recursion is not stationary (B = %f)", params[2])
+	}
+	dc.Omega, dc.A, dc.B = params[0], params[1], params[2]
+	return nil
+}
+
+// Fit estimates the parameters of the recursion by maximum likelihood
+// over the time-ordered observations. The recursion starts from the
+// static estimate of the parameter (see ArchimedeanCopula.Fit) which
+// also gives the starting point of the optimization (constant theta).
+func (dc *DynamicCopula) Fit(M *mat.Dense) (*FitResultN, error) {
+	n, _ := M.Dims()
+	if n < 2 {
+		return nil, errors.New("At least 2 observations are required")
+	}
+	static := &ArchimedeanCopula{theta: dc.Copula.theta, copula: dc.Copula.copula}
+	thetaStatic, _, _, _, err := static.maximizeLikelihood(M)
+	if err != nil {
+		return nil, err
+	}
+	dc.F0 = dc.transform(thetaStatic)
+
+	negLogLikelihood := func(x []float64) float64 {
+		if err := dc.SetParams(x); err != nil {
+			return math.Inf(1)
+		}
+		_, ll := dc.path(M)
+		return -ll
+	}
+	x0 := []float64{0.1 * dc.F0, 0., 0.9}
+	if dc.Dynamics == ScoreDriven {
+		x0[1] = 0.05
+	}
+	p := optimize.Problem{Func: negLogLikelihood}
+	s := optimize.Settings{FuncEvaluations: 20 * MaxFunEval}
+	result, err := optimize.Minimize(p, x0, &s, &optimize.NelderMead{})
+	if result == nil {
+		return nil, err
+	}
+	best := append([]float64{}, result.X...)
+	if errSet := dc.SetParams(best); errSet != nil {
+		return nil, errSet
+	}
+
+	fr := &FitResultN{
+		Params:        best,
+		LogLikelihood: -result.F,
+		StdErr:        []float64{math.NaN(), math.NaN(), math.NaN()},
+		Evals:         result.Stats.FuncEvaluations,
+		Message:       "Success",
+	}
+	if err != nil {
+		fr.Message = "Error: " + err.Error()
+	}
+	step := math.Min(1e-3, 0.5*(1.-math.Abs(best[2])))
+	cov, errCov := hessianCovariance(negLogLikelihood, best, step)
+	dc.SetParams(best)
+	if errCov != nil {
+		fr.Message += " (" + errCov.Error() + ")"
+		return fr, err
+	}
+	fr.Covariance = cov
+	fr.Correlation = mat.NewSymDense(3, nil)
+	for i := 0; i < 3; i++ {
+		fr.StdErr[i] = math.Sqrt(cov.At(i, i))
+	}
+	for i := 0; i < 3; i++ {
+		for j := i; j < 3; j++ {
+			fr.Correlation.SetSym(i, j, cov.At(i, j)/(fr.StdErr[i]*fr.StdErr[j]))
+		}
+	}
+	return fr, err
+}
+
+// Simulate draws size time-ordered observations of dimension dim from
+// the dynamic model and returns them along with their parameters
+func (dc *DynamicCopula) Simulate(size int, dim int, seed int64) (*mat.Dense, []float64) {
+	rng := rand.New(rand.NewSource(seed))
+	M := mat.NewDense(size, dim, nil)
+	theta := make([]float64, size)
+	f := dc.F0
+	for t := 0; t < size; t++ {
+		theta[t] = dc.theta(f)
+		cop := &ArchimedeanCopula{theta: theta[t], copula: dc.Copula.copula}
+		M.SetRow(t, cop.sampleWith(1, dim, rng).RawRowView(0))
+		f = dc.next(M, t, f)
+	}
+	return M, theta
+}
@@ -0,0 +1,107 @@
+// dynamic_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestInitDynamic(t *testing.T) {
+	title("Dynamic copulas")
+}
+
+func TestDynamicTransform(t *testing.T) {
+	checkTitle("Checking transformed parameter...")
+	dc := NewDynamicCopula(NewCopula("gumbel", 2.5), ScoreDriven)
+	if math.Abs(dc.theta(dc.F0)-2.5) > 1e-10 {
+		t.Errorf("Bad transform (%f instead of 2.5)", dc.theta(dc.F0))
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking constant dynamics...")
+	M := dc.Copula.Sample(50, 2)
+	theta := dc.Filter(M)
+	ok := len(theta) == 50
+	for _, x := range theta {
+		ok = ok && math.Abs(x-2.5) < 1e-10
+	}
+	if !ok || math.Abs(dc.LogLikelihood(M)-dc.Copula.LogLikelihood(M)) > 1e-8 {
+		t.Errorf("The parameter should not move before the fit")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestDynamicScoreDriven(t *testing.T) {
+	dc := NewDynamicCopula(NewCopula("clayton", 2.), ScoreDriven)
+	// theta oscillates around 2
+	dc.SetParams([]float64{0.03 * dc.F0, 0.08, 0.97})
+	M, truth := dc.Simulate(1000, 2, 7)
+
+	checkTitle("Checking GAS fit...")
+	fitted := NewDynamicCopula(NewCopula("clayton", 1.), ScoreDriven)
+	result, err := fitted.Fit(M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	static := NewCopula("clayton", 1.)
+	static.Fit(M)
+	filtered := fitted.Filter(M)
+	rho := stat.Correlation(filtered, truth, nil)
+	if result.LogLikelihood <= static.LogLikelihood(M) || rho < 0.5 {
+		t.Errorf("The filtered path should follow the true one (ℓ = %f, static ℓ = %f, ρ = %f)",
+			result.LogLikelihood, static.LogLikelihood(M), rho)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking GAS forecast...")
+	forecast := fitted.Forecast(M, 2000)
+	p := fitted.Params()
+	mean := fitted.theta(p[0] / (1. - p[2]))
+	if len(forecast) != 2000 || math.Abs(forecast[1999]-mean) > 1e-3 {
+		t.Errorf("The forecast should converge to %f (got %f)", mean, forecast[1999])
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestDynamicPatton(t *testing.T) {
+	// dependence jumps from theta = 1 to theta = 6 in the middle
+	low := NewCopula("gumbel", 1.2).Sample(400, 2)
+	high := NewCopula("gumbel", 6.).Sample(400, 2)
+	M := mat.NewDense(800, 2, nil)
+	M.Slice(0, 400, 0, 2).(*mat.Dense).Copy(low)
+	M.Slice(400, 800, 0, 2).(*mat.Dense).Copy(high)
+
+	checkTitle("Checking Patton fit...")
+	dc := NewDynamicCopula(NewCopula("gumbel", 2.), PattonDynamics)
+	if _, err := dc.Fit(M); err != nil {
+		t.Fatal(err)
+	}
+	theta := dc.Filter(M)
+	if mean(theta[450:]) < mean(theta[:400])+1. || dc.A > 0. {
+		t.Errorf("The filtered parameter should increase (%f -> %f, A = %f)",
+			mean(theta[:400]), mean(theta[450:]), dc.A)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking stationarity constraint...")
+	if err := dc.SetParams([]float64{0., 0., 1.}); err == nil {
+		t.Errorf("B = 1 should be rejected")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
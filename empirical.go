@@ -0,0 +1,240 @@
+// empirical.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/mathext"
+)
+
+// Copula is an interface satisfied by all the copulas of the package
+// (archimedean and nonparametric ones)
+type Copula interface {
+	Cdf(vector []float64) float64
+}
+
+// EmpiricalCopula is the empirical copula of a sample:
+// C_n(u) = 1/n sum_i 1{U_i <= u} where U_i are the pseudo-observations.
+// The counts are performed with a k-d tree.
+type EmpiricalCopula struct {
+	// U are the pseudo-observations of the sample
+	U    *mat.Dense
+	tree *kdTree
+}
+
+// NewEmpiricalCopula returns the empirical copula of the observations
+func NewEmpiricalCopula(X *mat.Dense) *EmpiricalCopula {
+	U := PseudoObservations(X)
+	return &EmpiricalCopula{U: U, tree: newKdTree(mat.DenseCopyOf(U))}
+}
+
+// Dim returns the dimension of the copula
+func (c *EmpiricalCopula) Dim() int {
+	_, d := c.U.Dims()
+	return d
+}
+
+// Cdf computes the proportion of the pseudo-observations lower than the vector
+func (c *EmpiricalCopula) Cdf(vector []float64) float64 {
+	n, _ := c.U.Dims()
+	return float64(c.tree.countLower(vector)) / float64(n)
+}
+
+// Sample draws pseudo-observations with replacement
+func (c *EmpiricalCopula) Sample(size int) *mat.Dense {
+	n, d := c.U.Dims()
+	M := mat.NewDense(size, d, nil)
+	for i := 0; i < size; i++ {
+		M.SetRow(i, c.U.RawRowView(rand.Intn(n)))
+	}
+	return M
+}
+
+// ranks returns the ranks (from 1 to n) of every column of X
+func ranks(X *mat.Dense) *mat.Dense {
+	n, _ := X.Dims()
+	R := PseudoObservations(X)
+	R.Apply(func(i, j int, v float64) float64 {
+		return math.Round(v * float64(n+1))
+	}, R)
+	return R
+}
+
+// CheckerboardCopula is the multilinear extension of the empirical copula:
+// every observation spreads a uniform mass over the cell of the grid
+// {0, 1/n, ..., 1}^d given by its ranks
+type CheckerboardCopula struct {
+	// R are the ranks of the sample
+	R *mat.Dense
+}
+
+// NewCheckerboardCopula returns the checkerboard copula of the observations
+func NewCheckerboardCopula(X *mat.Dense) *CheckerboardCopula {
+	return &CheckerboardCopula{R: ranks(X)}
+}
+
+// Dim returns the dimension of the copula
+func (c *CheckerboardCopula) Dim() int {
+	_, d := c.R.Dims()
+	return d
+}
+
+// Cdf computes 1/n sum_i prod_j min(max(n u_j - R_ij + 1, 0), 1)
+func (c *CheckerboardCopula) Cdf(vector []float64) float64 {
+	n, _ := c.R.Dims()
+	nF := float64(n)
+	s := 0.
+	for i := 0; i < n; i++ {
+		p := 1.
+		for j, r := range c.R.RawRowView(i) {
+			p *= math.Min(math.Max(nF*vector[j]-r+1., 0.), 1.)
+			if p == 0. {
+				break
+			}
+		}
+		s += p
+	}
+	return s / nF
+}
+
+// Pdf computes the density of the copula, i.e. n^(d-1) times the
+// number of observations whose cell contains the vector
+func (c *CheckerboardCopula) Pdf(vector []float64) float64 {
+	n, d := c.R.Dims()
+	nF := float64(n)
+	count := 0
+	for i := 0; i < n; i++ {
+		inside := true
+		for j, r := range c.R.RawRowView(i) {
+			if vector[j] <= (r-1.)/nF || vector[j] > r/nF {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			count++
+		}
+	}
+	return float64(count) * math.Pow(nF, float64(d-1))
+}
+
+// Sample draws a cell uniformly and then a point uniformly inside it
+func (c *CheckerboardCopula) Sample(size int) *mat.Dense {
+	n, d := c.R.Dims()
+	nF := float64(n)
+	M := mat.NewDense(size, d, nil)
+	for i := 0; i < size; i++ {
+		row := c.R.RawRowView(rand.Intn(n))
+		for j, r := range row {
+			M.Set(i, j, (r-1.+rand.Float64())/nF)
+		}
+	}
+	return M
+}
+
+// BernsteinCopula is the Bernstein smoothing of the empirical copula:
+// C_B(u) = sum_k C_n(k/m) prod_j binom(m, k_j) u_j^k_j (1-u_j)^(m-k_j).
+// It is a mixture of products of beta distributions: every observation
+// contributes prod_j Beta(a_ij, m - a_ij + 1) where a_ij = ceil(m U_ij),
+// so that the Cdf, the Pdf and the sampling cost O(nd).
+type BernsteinCopula struct {
+	// Degree is the degree m of the Bernstein polynomials
+	Degree int
+	// A are the shape parameters a_ij of every observation
+	A *mat.Dense
+}
+
+// NewBernsteinCopula returns the Bernstein copula of the observations.
+// The degree is set to n^(2/(d+4)) when it is not positive.
+func NewBernsteinCopula(X *mat.Dense, degree int) *BernsteinCopula {
+	n, d := X.Dims()
+	if degree <= 0 {
+		degree = int(math.Ceil(math.Pow(float64(n), 2./float64(d+4))))
+	}
+	m := float64(degree)
+	A := PseudoObservations(X)
+	A.Apply(func(i, j int, v float64) float64 {
+		return math.Max(math.Ceil(m*v), 1.)
+	}, A)
+	return &BernsteinCopula{Degree: degree, A: A}
+}
+
+// Dim returns the dimension of the copula
+func (c *BernsteinCopula) Dim() int {
+	_, d := c.A.Dims()
+	return d
+}
+
+// Cdf computes the cumulative distribution function of the copula
+func (c *BernsteinCopula) Cdf(vector []float64) float64 {
+	n, _ := c.A.Dims()
+	m := float64(c.Degree)
+	s := 0.
+	for i := 0; i < n; i++ {
+		p := 1.
+		for j, a := range c.A.RawRowView(i) {
+			u := math.Min(math.Max(vector[j], 0.), 1.)
+			p *= mathext.RegIncBeta(a, m-a+1., u)
+		}
+		s += p
+	}
+	return s / float64(n)
+}
+
+// Pdf computes the density of the copula
+func (c *BernsteinCopula) Pdf(vector []float64) float64 {
+	n, _ := c.A.Dims()
+	m := float64(c.Degree)
+	for _, u := range vector {
+		if u < 0. || u > 1. {
+			return 0.
+		}
+	}
+	// normalizing constants 1/B(a, m-a+1) for a = 1..m
+	norm := make([]float64, c.Degree+1)
+	for a := 1; a <= c.Degree; a++ {
+		lg, _ := math.Lgamma(m + 1.)
+		la, _ := math.Lgamma(float64(a))
+		lb, _ := math.Lgamma(m - float64(a) + 1.)
+		norm[a] = math.Exp(lg - la - lb)
+	}
+	s := 0.
+	for i := 0; i < n; i++ {
+		p := 1.
+		for j, a := range c.A.RawRowView(i) {
+			p *= norm[int(a)] * math.Pow(vector[j], a-1.) * math.Pow(1.-vector[j], m-a)
+		}
+		s += p
+	}
+	return s / float64(n)
+}
+
+// LogPdf computes the log density of the copula
+func (c *BernsteinCopula) LogPdf(vector []float64) float64 {
+	return math.Log(c.Pdf(vector))
+}
+
+// Sample draws an observation uniformly and then every coordinate from
+// its beta distribution (ratio of sums of exponential variables)
+func (c *BernsteinCopula) Sample(size int) *mat.Dense {
+	n, d := c.A.Dims()
+	M := mat.NewDense(size, d, nil)
+	for i := 0; i < size; i++ {
+		row := c.A.RawRowView(rand.Intn(n))
+		for j, a := range row {
+			x, y := 0., 0.
+			for k := 0; k < c.Degree+1; k++ {
+				if float64(k) < a {
+					x += rand.ExpFloat64()
+				} else {
+					y += rand.ExpFloat64()
+				}
+			}
+			M.Set(i, j, x/(x+y))
+		}
+	}
+	return M
+}
@@ -0,0 +1,117 @@
+// empirical_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitEmpirical(t *testing.T) {
+	title("Empirical copulas")
+}
+
+func TestEmpiricalCopula(t *testing.T) {
+	checkTitle("Checking empirical copula...")
+	X := mat.NewDense(4, 2, []float64{
+		10., -1.,
+		30., -3.,
+		20., -4.,
+		40., -2.,
+	})
+	c := NewEmpiricalCopula(X)
+	// pseudo-observations: (0.2, 0.8), (0.6, 0.4), (0.4, 0.2), (0.8, 0.6)
+	expected := map[[2]float64]float64{
+		{0.5, 0.5}: 0.25,
+		{0.7, 0.5}: 0.5,
+		{1., 1.}:   1.,
+		{0.1, 1.}:  0.,
+	}
+	for u, p := range expected {
+		if cdf := c.Cdf(u[:]); math.Abs(cdf-p) > 1e-12 {
+			t.Errorf("Bad empirical copula at %v, expected %f, got %f", u, p, cdf)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestCheckerboardCopula(t *testing.T) {
+	checkTitle("Checking checkerboard copula margins...")
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	X := M.Slice(0, 200, 0, 3).(*mat.Dense)
+	c := NewCheckerboardCopula(X)
+	for _, u := range []float64{0.123, 0.5, 0.87} {
+		if cdf := c.Cdf([]float64{1., u, 1.}); math.Abs(cdf-u) > 1e-12 {
+			t.Errorf("Bad margin, expected %f, got %f", u, cdf)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+
+	checkTitle("Checking checkerboard copula density...")
+	S := c.Sample(100)
+	for i := 0; i < 100; i++ {
+		if p := c.Pdf(S.RawRowView(i)); p < 200.*200. {
+			t.Errorf("The sample %v should lie in a cell (density %f)", S.RawRowView(i), p)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestBernsteinCopula(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	X := M.Slice(0, 500, 0, 2).(*mat.Dense)
+	c := NewBernsteinCopula(X, 0)
+	emp := NewEmpiricalCopula(X)
+
+	checkTitle("Checking Bernstein copula cdf...")
+	for _, u := range [][]float64{{0.2, 0.3}, {0.5, 0.5}, {0.9, 0.7}} {
+		if cdf, expected := c.Cdf(u), emp.Cdf(u); math.Abs(cdf-expected) > 0.05 {
+			t.Errorf("Bad Bernstein cdf at %v, expected %f, got %f", u, expected, cdf)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+
+	checkTitle("Checking Bernstein copula density...")
+	// the density integrates to 1 (midpoint rule)
+	m := 50
+	integral := 0.
+	for a := 0; a < m; a++ {
+		for b := 0; b < m; b++ {
+			integral += c.Pdf([]float64{(float64(a) + 0.5) / float64(m), (float64(b) + 0.5) / float64(m)})
+		}
+	}
+	integral /= float64(m * m)
+	if math.Abs(integral-1.) > 0.01 {
+		t.Errorf("Bad Bernstein density, expected an integral of 1, got %f", integral)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Bernstein copula sampling...")
+	S := NewEmpiricalCopula(c.Sample(2000))
+	for _, u := range [][]float64{{0.2, 0.3}, {0.5, 0.5}, {0.9, 0.7}} {
+		if cdf, expected := S.Cdf(u), c.Cdf(u); math.Abs(cdf-expected) > 0.05 {
+			t.Errorf("Bad sample at %v, expected %f, got %f", u, expected, cdf)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
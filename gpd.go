@@ -0,0 +1,143 @@
+// gpd.go
+
+package gopula
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// GeneralizedPareto is the generalized Pareto distribution of the
+// excesses Y = X - t over a high threshold t (Pickands-Balkema-de Haan
+// theorem): P(Y > y) = (1 + Shape y / Scale)^(-1/Shape)
+// (exp(-y / Scale) when Shape = 0)
+type GeneralizedPareto struct {
+	Shape float64
+	Scale float64
+}
+
+// Survival computes P(Y > y)
+func (g *GeneralizedPareto) Survival(y float64) float64 {
+	if y <= 0. {
+		return 1.
+	}
+	if g.Shape == 0. {
+		return math.Exp(-y / g.Scale)
+	}
+	z := 1. + g.Shape*y/g.Scale
+	if z <= 0. {
+		// beyond the upper end point (negative shape)
+		return 0.
+	}
+	return math.Pow(z, -1./g.Shape)
+}
+
+// CDF computes P(Y <= y)
+func (g *GeneralizedPareto) CDF(y float64) float64 {
+	return 1. - g.Survival(y)
+}
+
+// LogProb computes the log-density at y
+func (g *GeneralizedPareto) LogProb(y float64) float64 {
+	if y < 0. {
+		return math.Inf(-1)
+	}
+	if g.Shape == 0. {
+		return -math.Log(g.Scale) - y/g.Scale
+	}
+	z := 1. + g.Shape*y/g.Scale
+	if z <= 0. {
+		return math.Inf(-1)
+	}
+	return -math.Log(g.Scale) - (1.+1./g.Shape)*math.Log(z)
+}
+
+// Quantile computes the excess y such that P(Y <= y) = p
+func (g *GeneralizedPareto) Quantile(p float64) float64 {
+	if g.Shape == 0. {
+		return -g.Scale * math.Log(1.-p)
+	}
+	return g.Scale / g.Shape * (math.Pow(1.-p, -g.Shape) - 1.)
+}
+
+// LogLikelihood computes the log-likelihood of the excesses
+func (g *GeneralizedPareto) LogLikelihood(y []float64) float64 {
+	ll := 0.
+	for _, v := range y {
+		ll += g.LogProb(v)
+	}
+	return ll
+}
+
+// gpdProfile computes the opposite of the profile log-likelihood (divided
+// by the number of excesses) at x = Shape / Scale. Given x, the likelihood
+// is maximized by Shape = mean(log(1 + x y)) (Grimshaw, 1993).
+func gpdProfile(x float64, args interface{}) float64 {
+	y := args.([]float64)
+	if x == 0. {
+		// exponential limit
+		return math.Log(mean(y)) + 1.
+	}
+	v := 0.
+	for _, yi := range y {
+		v += math.Log1p(x * yi)
+	}
+	v /= float64(len(y))
+	if math.IsNaN(v) || math.IsInf(v, 0) || v/x <= 0. {
+		return math.Inf(1)
+	}
+	return math.Log(v/x) + v + 1.
+}
+
+// FitGeneralizedPareto estimates the parameters of the generalized Pareto
+// distribution of the excesses by maximum likelihood. The profile
+// likelihood in x = Shape / Scale is first evaluated on a grid covering
+// (-1 / max(y), +inf) (the optimum may be local) and then refined with the
+// Brent's method around the best node.
+func FitGeneralizedPareto(y []float64) (*GeneralizedPareto, error) {
+	if len(y) < 2 {
+		return nil, errors.New("At least 2 excesses are required")
+	}
+	if min(y) < 0. {
+		return nil, errors.New("The excesses must be non-negative")
+	}
+	ym, yM := mean(y), max(y)
+	if yM == 0. {
+		return nil, errors.New("The excesses are all null")
+	}
+
+	grid := []float64{0.}
+	for k := 1; k < 20; k++ {
+		grid = append(grid, -float64(k)/(20.*yM))
+	}
+	grid = append(grid, -0.999/yM, -0.999999/yM)
+	for k := -16; k <= 12; k++ {
+		grid = append(grid, math.Pow(10., float64(k)/4.)/ym)
+	}
+	sort.Float64s(grid)
+	best := 0
+	values := make([]float64, len(grid))
+	for k, x := range grid {
+		values[k] = gpdProfile(x, y)
+		if values[k] < values[best] {
+			best = k
+		}
+	}
+	a := grid[int(math.Max(0., float64(best-1)))]
+	b := grid[int(math.Min(float64(len(grid)-1), float64(best+1)))]
+	x, f, _, err := BrentMinimizer(gpdProfile, y, a, b, 1e-10/ym)
+	if err != nil || !(f <= values[best]) {
+		x = grid[best]
+	}
+
+	if x == 0. {
+		return &GeneralizedPareto{Shape: 0., Scale: ym}, nil
+	}
+	v := 0.
+	for _, yi := range y {
+		v += math.Log1p(x * yi)
+	}
+	v /= float64(len(y))
+	return &GeneralizedPareto{Shape: v, Scale: v / x}, nil
+}
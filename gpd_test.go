@@ -0,0 +1,87 @@
+// gpd_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestInitGPD(t *testing.T) {
+	title("Generalized Pareto distribution")
+}
+
+// gpdSample draws excesses from a generalized Pareto distribution
+func gpdSample(g *GeneralizedPareto, n int, seed int64) []float64 {
+	rng := rand.New(rand.NewSource(seed))
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = g.Quantile(rng.Float64())
+	}
+	return y
+}
+
+func TestGeneralizedPareto(t *testing.T) {
+	checkTitle("Checking quantile...")
+	g := &GeneralizedPareto{Shape: 0.3, Scale: 2.}
+	ok := true
+	for _, p := range []float64{0.1, 0.5, 0.99} {
+		if math.Abs(g.CDF(g.Quantile(p))-p) > 1e-12 {
+			ok = false
+		}
+	}
+	e := &GeneralizedPareto{Shape: 0., Scale: 2.}
+	if math.Abs(e.Quantile(0.5)-2.*math.Log(2.)) > 1e-12 {
+		ok = false
+	}
+	if !ok {
+		t.Errorf("The quantile should invert the cdf")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking end point...")
+	b := &GeneralizedPareto{Shape: -0.5, Scale: 1.}
+	if b.Survival(2.5) != 0. || !math.IsInf(b.LogProb(2.5), -1) || b.Survival(-1.) != 1. {
+		t.Errorf("The distribution should be bounded by 2")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestFitGeneralizedPareto(t *testing.T) {
+	for _, g := range []*GeneralizedPareto{
+		{Shape: 0.3, Scale: 2.},
+		{Shape: 0., Scale: 1.},
+		{Shape: -0.3, Scale: 0.5},
+	} {
+		checkTitle(fmt.Sprintf("Checking GPD fit (shape %.1f)...", g.Shape))
+		y := gpdSample(g, 5000, 1)
+		fit, err := FitGeneralizedPareto(y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(fit.Shape-g.Shape) > 0.05 || math.Abs(fit.Scale/g.Scale-1.) > 0.05 ||
+			fit.LogLikelihood(y) < g.LogLikelihood(y) {
+			t.Errorf("Bad fit (shape = %f, scale = %f)", fit.Shape, fit.Scale)
+			testERROR()
+		} else {
+			testOK()
+		}
+	}
+
+	checkTitle("Checking GPD fit arguments...")
+	_, err1 := FitGeneralizedPareto([]float64{1.})
+	_, err2 := FitGeneralizedPareto([]float64{1., -1.})
+	_, err3 := FitGeneralizedPareto([]float64{0., 0.})
+	if err1 == nil || err2 == nil || err3 == nil {
+		t.Errorf("The excesses should be invalid")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
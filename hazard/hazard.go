@@ -0,0 +1,145 @@
+// hazard.go
+
+// Package hazard computes multivariate return periods (as used in
+// hydrology) from a fitted archimedean copula. All the events are
+// given on the uniform scale (u_j = F_j(x_j)).
+package hazard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/asiffer/gopula"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Analysis gathers a fitted copula and the mean interarrival time
+// of the events (e.g. 1 year for annual maxima)
+type Analysis struct {
+	// Copula is the fitted dependence structure
+	Copula *gopula.ArchimedeanCopula
+	// Mu is the mean interarrival time of the events
+	Mu float64
+	// Dim is the dimension of the events
+	Dim int
+}
+
+// NewAnalysis returns a bivariate analysis with the given copula and
+// mean interarrival time
+func NewAnalysis(copula *gopula.ArchimedeanCopula, mu float64) *Analysis {
+	return &Analysis{Copula: copula, Mu: mu, Dim: 2}
+}
+
+// check ensures that the event has the right dimension
+func (a *Analysis) check(u []float64) error {
+	if len(u) != a.Dim {
+		return fmt.Errorf("The event has %d coordinates while the analysis is %d-dimensional", len(u), a.Dim)
+	}
+	return nil
+}
+
+// AndReturnPeriod computes the return period of the event where all the
+// variables exceed their threshold: mu / P(U > u)
+func (a *Analysis) AndReturnPeriod(u []float64) (float64, error) {
+	if err := a.check(u); err != nil {
+		return math.NaN(), err
+	}
+	return a.Mu / a.Copula.Survival(u), nil
+}
+
+// OrReturnPeriod computes the return period of the event where at least
+// one variable exceeds its threshold: mu / (1 - C(u))
+func (a *Analysis) OrReturnPeriod(u []float64) (float64, error) {
+	if err := a.check(u); err != nil {
+		return math.NaN(), err
+	}
+	return a.Mu / (1. - a.Copula.Cdf(u)), nil
+}
+
+// ConditionalReturnPeriod computes the return period of the event where
+// all the variables exceed their threshold given that the j-th one does:
+// mu / P(U > u | U_j > u_j)
+func (a *Analysis) ConditionalReturnPeriod(u []float64, j int) (float64, error) {
+	if err := a.check(u); err != nil {
+		return math.NaN(), err
+	}
+	if j < 0 || j >= a.Dim {
+		return math.NaN(), fmt.Errorf("Bad conditioning variable %d", j)
+	}
+	return a.Mu * (1. - u[j]) / a.Copula.Survival(u), nil
+}
+
+// Kendall computes the Kendall distribution function K(t) = P(C(U) <= t)
+func (a *Analysis) Kendall(t float64) float64 {
+	return a.Copula.KendallCdf(t, a.Dim)
+}
+
+// KendallReturnPeriod computes the return period of the events lying
+// beyond the critical level t: mu / (1 - K(t))
+func (a *Analysis) KendallReturnPeriod(t float64) float64 {
+	return a.Mu / (1. - a.Kendall(t))
+}
+
+// KendallLevel returns the critical level t whose Kendall return period
+// is T, i.e. the solution of K(t) = 1 - mu / T
+func (a *Analysis) KendallLevel(T float64) (float64, error) {
+	if T <= a.Mu {
+		return math.NaN(), errors.New("The return period must be greater than the interarrival time")
+	}
+	t := a.Copula.KendallPpf(1.-a.Mu/T, a.Dim)
+	if math.IsNaN(t) {
+		return t, errors.New("The critical level has not been found")
+	}
+	return t, nil
+}
+
+// levelCurve returns the second coordinate of the point of the
+// bivariate critical level curve C(u, v) = t
+func (a *Analysis) levelCurve(u float64, t float64) float64 {
+	return a.Copula.Psi(a.Copula.PsiInv(t) - a.Copula.PsiInv(u))
+}
+
+// LevelCurve samples n points of the bivariate critical level curve
+// C(u, v) = t (one row per point)
+func (a *Analysis) LevelCurve(t float64, n int) (*mat.Dense, error) {
+	if a.Dim != 2 {
+		return nil, errors.New("The level curves are only available in the bivariate case")
+	}
+	if t <= 0. || t >= 1. || n < 2 {
+		return nil, errors.New("The level must lie in (0, 1) and at least 2 points are required")
+	}
+	curve := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		u := t + (1.-t)*float64(i)/float64(n-1)
+		curve.Set(i, 0, u)
+		curve.Set(i, 1, a.levelCurve(u, t))
+	}
+	return curve, nil
+}
+
+// DesignEvent returns the most likely event of the bivariate critical
+// level curve whose Kendall return period is T, i.e. the point of the
+// curve maximizing the density of the copula (Salvadori et al., 2011)
+func (a *Analysis) DesignEvent(T float64) ([]float64, error) {
+	if a.Dim != 2 {
+		return nil, errors.New("The design events are only available in the bivariate case")
+	}
+	t, err := a.KendallLevel(T)
+	if err != nil {
+		return nil, err
+	}
+	fun := func(u float64, args interface{}) float64 {
+		lpdf := a.Copula.LogPdf([]float64{u, a.levelCurve(u, t)})
+		if math.IsNaN(lpdf) {
+			return math.Inf(1)
+		}
+		return -lpdf
+	}
+	eps := 1e-9 * (1. - t)
+	u, _, _, err := gopula.BrentMinimizer(fun, nil, t+eps, 1.-eps, 1e-10)
+	if err != nil {
+		return nil, err
+	}
+	return []float64{u, a.levelCurve(u, t)}, nil
+}
@@ -0,0 +1,99 @@
+// hazard_test.go
+
+package hazard
+
+import (
+	"math"
+	"testing"
+
+	"github.com/asiffer/gopula"
+)
+
+func TestKendall(t *testing.T) {
+	theta := 2.
+	a := NewAnalysis(gopula.NewCopula("clayton", theta), 1.)
+	for _, x := range []float64{0.01, 0.2, 0.5, 0.9} {
+		// closed form of the bivariate clayton copula
+		expected := x + x*(1.-math.Pow(x, theta))/theta
+		if k := a.Kendall(x); math.Abs(k-expected) > 1e-9 {
+			t.Errorf("Bad Kendall function at %f, expected %f, got %f", x, expected, k)
+		}
+	}
+	if a.Kendall(0.) != 0. || a.Kendall(1.) != 1. {
+		t.Errorf("Bad Kendall function at the bounds")
+	}
+}
+
+func TestReturnPeriods(t *testing.T) {
+	a := NewAnalysis(gopula.NewCopula("gumbel", 2.), 1.)
+	u := []float64{0.99, 0.99}
+	and, err := a.AndReturnPeriod(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	or, err := a.OrReturnPeriod(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the univariate return period lies in between
+	if !(or < 100. && 100. < and) {
+		t.Errorf("Bad return periods, expected OR < 100 < AND, got OR = %f, AND = %f", or, and)
+	}
+	cond, err := a.ConditionalReturnPeriod(u, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(cond-and/100.) > 1e-9 {
+		t.Errorf("Bad conditional return period, expected %f, got %f", and/100., cond)
+	}
+	if _, err := a.AndReturnPeriod([]float64{0.5}); err == nil {
+		t.Errorf("The event should not match the dimension")
+	}
+	if _, err := a.ConditionalReturnPeriod(u, 2); err == nil {
+		t.Errorf("The conditioning variable should not exist")
+	}
+}
+
+func TestKendallLevel(t *testing.T) {
+	a := NewAnalysis(gopula.NewCopula("frank", 5.), 1.)
+	level, err := a.KendallLevel(50.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if T := a.KendallReturnPeriod(level); math.Abs(T-50.) > 1e-4 {
+		t.Errorf("Bad Kendall level, expected T = 50, got %f", T)
+	}
+	if _, err := a.KendallLevel(0.5); err == nil {
+		t.Errorf("The return period should be too small")
+	}
+}
+
+func TestLevelCurve(t *testing.T) {
+	a := NewAnalysis(gopula.NewCopula("clayton", 2.), 1.)
+	curve, err := a.LevelCurve(0.8, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if c := a.Copula.Cdf(curve.RawRowView(i)); math.Abs(c-0.8) > 1e-9 {
+			t.Errorf("Bad level curve, expected C = 0.8, got %f at %v", c, curve.RawRowView(i))
+			return
+		}
+	}
+}
+
+func TestDesignEvent(t *testing.T) {
+	a := NewAnalysis(gopula.NewCopula("gumbel", 3.), 1.)
+	event, err := a.DesignEvent(100.)
+	if err != nil {
+		t.Fatal(err)
+	}
+	level, _ := a.KendallLevel(100.)
+	if c := a.Copula.Cdf(event); math.Abs(c-level) > 1e-6 {
+		t.Errorf("The design event is not on the level curve, expected C = %f, got %f", level, c)
+	}
+	// the copula is exchangeable
+	if math.Abs(event[0]-event[1]) > 1e-3 {
+		t.Errorf("The design event should be symmetric, got %v", event)
+	}
+}
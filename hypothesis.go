@@ -0,0 +1,210 @@
+// hypothesis.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// rankTestPValue runs the replicates of a randomization test in parallel
+// (every one with its own random source seeded from opts.Seed) and
+// returns the result of the test
+func rankTestPValue(statistic float64, opts *BootstrapOptions, replicate func(rng *rand.Rand) float64) *TestResult {
+	replicates := make([]float64, opts.Replicates)
+	parallelize(opts.Replicates, opts.Workers, func(b int) {
+		replicates[b] = replicate(rand.New(rand.NewSource(opts.Seed + int64(b))))
+	})
+	return &TestResult{
+		Statistic:  statistic,
+		PValue:     bootstrapPValue(statistic, replicates),
+		Replicates: replicates,
+	}
+}
+
+// independenceKernel computes for every column the kernel
+// D(i, k) = (2n+1)/6n + r_i(r_i-1)/2n(n+1) + r_k(r_k-1)/2n(n+1) - max(r_i, r_k)/(n+1)
+// where r are the ranks of the column. Only the ranks and the terms
+// r(r-1)/2n(n+1) are stored so that the memory remains O(n d).
+type independenceKernel struct {
+	n     int
+	ranks [][]float64
+	terms [][]float64
+}
+
+// newIndependenceKernel prepares the kernels of the columns of the ranks R
+func newIndependenceKernel(R *mat.Dense) *independenceKernel {
+	n, d := R.Dims()
+	nF := float64(n)
+	ik := &independenceKernel{n: n, ranks: make([][]float64, d), terms: make([][]float64, d)}
+	for j := 0; j < d; j++ {
+		ik.ranks[j] = rawCol(R, j)
+		ik.terms[j] = make([]float64, n)
+		for i, r := range ik.ranks[j] {
+			ik.terms[j][i] = r * (r - 1.) / (2. * nF * (nF + 1.))
+		}
+	}
+	return ik
+}
+
+// at computes the kernel D(i, k) of the j-th column
+func (ik *independenceKernel) at(j int, i int, k int) float64 {
+	nF := float64(ik.n)
+	return (2.*nF+1.)/(6.*nF) + ik.terms[j][i] + ik.terms[j][k] -
+		math.Max(ik.ranks[j][i], ik.ranks[j][k])/(nF+1.)
+}
+
+// independenceStatistic computes the sum over the subsets A of at least
+// two columns of the Cramér-von Mises statistics of the Möbius
+// decomposition T_A = 1/n sum_i sum_k prod_{j in A} D_j(p_j(i), p_j(k))
+// where p_j permutes the rows of the j-th column. The sum over the subsets
+// is computed in O(n² d) with the identity
+// sum_{|A| >= 2} prod_{j in A} D_j = prod_j (1 + D_j) - 1 - sum_j D_j.
+func independenceStatistic(ik *independenceKernel, perms [][]int) float64 {
+	n := ik.n
+	T := 0.
+	for i := 0; i < n; i++ {
+		for k := 0; k < n; k++ {
+			p, s := 1., 0.
+			for j, pj := range perms {
+				v := ik.at(j, pj[i], pj[k])
+				p *= 1. + v
+				s += v
+			}
+			T += p - 1. - s
+		}
+	}
+	return T / float64(n)
+}
+
+// IndependenceTest tests the mutual independence of the columns of M
+// with the rank-based Cramér-von Mises statistic of Genest and Rémillard
+// (2004): the sum of the statistics of the Möbius decomposition of the
+// empirical copula process over all the subsets of at least two columns.
+// The p-value is computed by permuting the rows of every column
+// independently (opts.Replicates permutations).
+func IndependenceTest(M *mat.Dense, opts *BootstrapOptions) (*TestResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if n < 2 || d < 2 {
+		return nil, fmt.Errorf("At least 2 observations with 2 columns are required (got %dx%d)", n, d)
+	}
+	ik := newIndependenceKernel(ranks(M))
+	identity := make([][]int, d)
+	for j := range identity {
+		identity[j] = make([]int, n)
+		for i := range identity[j] {
+			identity[j][i] = i
+		}
+	}
+	statistic := independenceStatistic(ik, identity)
+	return rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		perms := make([][]int, d)
+		perms[0] = identity[0]
+		for j := 1; j < d; j++ {
+			perms[j] = rng.Perm(n)
+		}
+		return independenceStatistic(ik, perms)
+	}), nil
+}
+
+// exchangeabilityStatistic computes sum_i (C_n(u_i, v_i) - C_n(v_i, u_i))²
+// where C_n is the empirical copula of the pairs (u, v)
+func exchangeabilityStatistic(U *mat.Dense) float64 {
+	n, _ := U.Dims()
+	tree := newKdTree(U)
+	s := 0.
+	swapped := make([]float64, 2)
+	for i := 0; i < n; i++ {
+		row := U.RawRowView(i)
+		swapped[0], swapped[1] = row[1], row[0]
+		delta := float64(tree.countLower(row)-tree.countLower(swapped)) / float64(n)
+		s += delta * delta
+	}
+	return s
+}
+
+// ExchangeabilityTest tests whether the columns j and k of M are
+// exchangeable, i.e. C(u, v) = C(v, u) (Genest, Nešlehová and Quessy,
+// 2012). The statistic is the Cramér-von Mises distance between the
+// empirical copula of the pair and its transpose. The p-value is computed
+// by randomly swapping the coordinates of every observation, which leaves
+// the distribution unchanged under exchangeability (the swapped
+// observations are ranked again to get uniform margins).
+func ExchangeabilityTest(M *mat.Dense, j int, k int, opts *BootstrapOptions) (*TestResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if j < 0 || k < 0 || j >= d || k >= d || j == k {
+		return nil, fmt.Errorf("Bad pair of columns (%d, %d)", j, k)
+	}
+	pair := mat.NewDense(n, 2, nil)
+	pair.SetCol(0, rawCol(M, j))
+	pair.SetCol(1, rawCol(M, k))
+	U := PseudoObservations(pair)
+	statistic := exchangeabilityStatistic(U)
+	return rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		S := mat.DenseCopyOf(U)
+		for i := 0; i < n; i++ {
+			if rng.Intn(2) == 1 {
+				row := S.RawRowView(i)
+				row[0], row[1] = row[1], row[0]
+			}
+		}
+		return exchangeabilityStatistic(PseudoObservations(S))
+	}), nil
+}
+
+// radialStatistic computes sum_i (C_n(U_i) - S_n(U_i))² where S_n is the
+// empirical copula of the reflected observations 1 - U
+func radialStatistic(U *mat.Dense) float64 {
+	n, _ := U.Dims()
+	R := mat.DenseCopyOf(U)
+	R.Apply(func(i, j int, v float64) float64 { return 1. - v }, R)
+	tree := newKdTree(U)
+	reflected := newKdTree(R)
+	s := 0.
+	for i := 0; i < n; i++ {
+		row := U.RawRowView(i)
+		delta := float64(tree.countLower(row)-reflected.countLower(row)) / float64(n)
+		s += delta * delta
+	}
+	return s
+}
+
+// RadialSymmetryTest tests whether the copula of M is radially symmetric,
+// i.e. U and 1 - U have the same distribution (Genest and Nešlehová,
+// 2014). The statistic is the Cramér-von Mises distance between the
+// empirical copula and the one of the reflected pseudo-observations. The
+// p-value is computed by randomly reflecting every observation, which
+// leaves the distribution unchanged under radial symmetry (the reflected
+// observations are ranked again to get uniform margins).
+func RadialSymmetryTest(M *mat.Dense, opts *BootstrapOptions) (*TestResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if n < 2 || d < 2 {
+		return nil, fmt.Errorf("At least 2 observations with 2 columns are required (got %dx%d)", n, d)
+	}
+	U := PseudoObservations(M)
+	statistic := radialStatistic(U)
+	return rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		S := mat.DenseCopyOf(U)
+		for i := 0; i < n; i++ {
+			if rng.Intn(2) == 1 {
+				row := S.RawRowView(i)
+				for j := range row {
+					row[j] = 1. - row[j]
+				}
+			}
+		}
+		return radialStatistic(PseudoObservations(S))
+	}), nil
+}
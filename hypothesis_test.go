@@ -0,0 +1,172 @@
+// hypothesis_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitHypothesis(t *testing.T) {
+	title("Hypothesis tests")
+}
+
+// independentSample draws a sample with independent uniform columns
+func independentSample(n int, d int, seed int64) *mat.Dense {
+	rng := rand.New(rand.NewSource(seed))
+	M := mat.NewDense(n, d, nil)
+	M.Apply(func(i, j int, v float64) float64 { return rng.Float64() }, M)
+	return M
+}
+
+func TestIndependenceTest(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 100
+
+	checkTitle("Checking independence test (dependent)...")
+	result, err := IndependenceTest(M.Slice(0, 100, 0, 3).(*mat.Dense), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 0.05 {
+		t.Errorf("The independence should be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking independence test (independent)...")
+	result, err = IndependenceTest(independentSample(100, 3, 1), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 {
+		t.Errorf("The independence should not be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestIndependenceStatistic(t *testing.T) {
+	checkTitle("Checking independence statistic...")
+	n, d := 30, 4
+	ik := newIndependenceKernel(ranks(independentSample(n, d, 5)))
+	rng := rand.New(rand.NewSource(6))
+	perms := make([][]int, d)
+	for j := range perms {
+		perms[j] = rng.Perm(n)
+	}
+	// explicit sum over the subsets of at least two columns
+	expected := 0.
+	for A := 1; A < 1<<uint(d); A++ {
+		columns := make([]int, 0)
+		for j := 0; j < d; j++ {
+			if A&(1<<uint(j)) != 0 {
+				columns = append(columns, j)
+			}
+		}
+		if len(columns) < 2 {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			for k := 0; k < n; k++ {
+				p := 1.
+				for _, j := range columns {
+					p *= ik.at(j, perms[j][i], perms[j][k])
+				}
+				expected += p / float64(n)
+			}
+		}
+	}
+	if T := independenceStatistic(ik, perms); math.Abs(T-expected) > 1e-10*math.Abs(expected) {
+		t.Errorf("Bad independence statistic, expected %f, got %f", expected, T)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestExchangeabilityTest(t *testing.T) {
+	M := NewCopula("clayton", 2.1).sampleWith(300, 3, rand.New(rand.NewSource(4)))
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 100
+
+	checkTitle("Checking exchangeability test (clayton)...")
+	result, err := ExchangeabilityTest(M, 0, 2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 {
+		t.Errorf("The exchangeability should not be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking exchangeability test (asymmetric)...")
+	// V = U + 0.3 (mod 1) while U = V + 0.7 (mod 1)
+	X := independentSample(300, 2, 2)
+	for i := 0; i < 300; i++ {
+		v := X.At(i, 0) + 0.3 + 0.05*X.At(i, 1)
+		X.Set(i, 1, v-math.Floor(v))
+	}
+	result, err = ExchangeabilityTest(X, 0, 1, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 0.05 {
+		t.Errorf("The exchangeability should be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking exchangeability test arguments...")
+	if _, err := ExchangeabilityTest(X, 1, 1, opts); err == nil {
+		t.Errorf("The pair of columns should be invalid")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestRadialSymmetryTest(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 100
+
+	checkTitle("Checking radial symmetry test (clayton)...")
+	result, err := RadialSymmetryTest(M.Slice(0, 500, 0, 2).(*mat.Dense), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 0.05 {
+		t.Errorf("The radial symmetry should be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking radial symmetry test (independent)...")
+	result, err = RadialSymmetryTest(independentSample(300, 2, 3), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 {
+		t.Errorf("The radial symmetry should not be rejected (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,87 @@
+// influence.go
+
+package gopula
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// PointwiseLogLikelihood computes the log-density of the copula at
+// every observation. Unlike LogLikelihood, the NaN contributions are kept.
+func (arch *ArchimedeanCopula) PointwiseLogLikelihood(M *mat.Dense) []float64 {
+	return logDensities(arch, M)
+}
+
+// InvalidRows returns the indices of the observations whose
+// log-density is NaN or infinite
+func (arch *ArchimedeanCopula) InvalidRows(M *mat.Dense) []int {
+	rows := make([]int, 0)
+	for i, l := range arch.PointwiseLogLikelihood(M) {
+		if math.IsNaN(l) || math.IsInf(l, 0) {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+// InfluenceResult details the influence of every observation on
+// the estimated parameter
+type InfluenceResult struct {
+	// LogLikelihood are the log-density contributions
+	LogLikelihood []float64
+	// Invalid tells whether the contribution is NaN or infinite
+	// (these observations have NaN influence)
+	Invalid []bool
+	// Score are the derivatives of the contributions with respect to theta
+	Score []float64
+	// Delta are the approximate leave-one-out changes of the estimate
+	// theta_(-i) - theta = -s_i / I
+	Delta []float64
+	// Cook are the Cook's distances D_i = Delta_i² I = s_i² / I
+	Cook []float64
+	// Outliers are the indices of the observations whose Cook's
+	// distance exceeds 4/n
+	Outliers []int
+}
+
+// Influence computes the influence diagnostics of the observations.
+// The parameter theta must be the fitted value: the leave-one-out
+// estimates are approximated by a single Newton step from theta,
+// using the score s_i of the removed observation and the observed
+// information I of the sample.
+func (arch *ArchimedeanCopula) Influence(M *mat.Dense) (*InfluenceResult, error) {
+	n, _ := M.Dims()
+	info := arch.ObservedInformation(M)
+	if !(info > 0.) {
+		return nil, errors.New("The observed information is not positive")
+	}
+	h := arch.thetaStep()
+	ir := &InfluenceResult{
+		LogLikelihood: arch.PointwiseLogLikelihood(M),
+		Invalid:       make([]bool, n),
+		Score:         make([]float64, n),
+		Delta:         make([]float64, n),
+		Cook:          make([]float64, n),
+		Outliers:      make([]int, 0),
+	}
+	threshold := 4. / float64(n)
+	for i := 0; i < n; i++ {
+		l := ir.LogLikelihood[i]
+		s := arch.scoreTheta(M.RawRowView(i), h)
+		if math.IsNaN(l) || math.IsInf(l, 0) || math.IsNaN(s) || math.IsInf(s, 0) {
+			ir.Invalid[i] = true
+			ir.Score[i], ir.Delta[i], ir.Cook[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		ir.Score[i] = s
+		ir.Delta[i] = -s / info
+		ir.Cook[i] = s * s / info
+		if ir.Cook[i] > threshold {
+			ir.Outliers = append(ir.Outliers, i)
+		}
+	}
+	return ir, nil
+}
@@ -0,0 +1,94 @@
+// influence_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitInfluence(t *testing.T) {
+	title("Influence")
+}
+
+// contaminatedSample returns the first rows of the clayton sample
+// followed by an outlier and an invalid row
+func contaminatedSample(t *testing.T, n int) *mat.Dense {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	X := mat.NewDense(n+2, 2, nil)
+	X.Slice(0, n, 0, 2).(*mat.Dense).Copy(M.Slice(0, n, 0, 2))
+	X.SetRow(n, []float64{0.002, 0.998})
+	X.SetRow(n+1, []float64{math.NaN(), 0.5})
+	return X
+}
+
+func TestPointwiseLogLikelihood(t *testing.T) {
+	X := contaminatedSample(t, 200)
+	AC := NewCopula("clayton", 2.)
+
+	checkTitle("Checking pointwise log-likelihood...")
+	l := AC.PointwiseLogLikelihood(X)
+	s := 0.
+	for _, x := range l[:201] {
+		s += x
+	}
+	if len(l) != 202 || math.Abs(s-AC.LogLikelihood(X)) > 1e-8 {
+		t.Errorf("The contributions should sum to the log-likelihood (%f != %f)", s, AC.LogLikelihood(X))
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking invalid rows...")
+	rows := AC.InvalidRows(X)
+	if len(rows) != 1 || rows[0] != 201 {
+		t.Errorf("The last row should be invalid (got %v)", rows)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestInfluence(t *testing.T) {
+	X := contaminatedSample(t, 200)
+	AC := NewCopula("clayton", 2.)
+	AC.Fit(X)
+	theta := AC.Theta()
+
+	checkTitle("Checking influence diagnostics...")
+	ir, err := AC.Influence(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worst := 0
+	for i, c := range ir.Cook {
+		if c > ir.Cook[worst] {
+			worst = i
+		}
+	}
+	flagged := false
+	for _, i := range ir.Outliers {
+		flagged = flagged || i == 200
+	}
+	if worst != 200 || !flagged || !ir.Invalid[201] || !math.IsNaN(ir.Delta[201]) {
+		t.Errorf("The outlier should have the largest Cook's distance (got row %d)", worst)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking leave-one-out approximation...")
+	AC.Fit(mat.DenseCopyOf(X.Slice(0, 200, 0, 2)))
+	exact := AC.Theta() - theta
+	if math.Abs(ir.Delta[200]-exact) > 0.3*math.Abs(exact) {
+		t.Errorf("Bad leave-one-out change (%f instead of %f)", ir.Delta[200], exact)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,129 @@
+// joint.go
+
+package gopula
+
+import (
+	"fmt"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// JointDistribution is a multivariate distribution made of
+// univariate margins coupled by an archimedean copula. The margins
+// may be continuous or discrete (see DiscreteMargin).
+type JointDistribution struct {
+	// Copula is the dependence structure
+	Copula *ArchimedeanCopula
+	// Margins are the univariate distributions of every coordinate
+	Margins []Margin
+}
+
+// NewJointDistribution returns the joint distribution made of the
+// given copula and margins
+func NewJointDistribution(copula *ArchimedeanCopula, margins []Margin) *JointDistribution {
+	return &JointDistribution{Copula: copula, Margins: margins}
+}
+
+// Dim returns the dimension of the distribution
+func (jd *JointDistribution) Dim() int {
+	return len(jd.Margins)
+}
+
+// Cdf computes the cumulative distribution function C(F_1(x_1), ..., F_d(x_d))
+func (jd *JointDistribution) Cdf(x []float64) float64 {
+	u := make([]float64, len(x))
+	for j, m := range jd.Margins {
+		u[j] = m.CDF(x[j])
+	}
+	return jd.Copula.Cdf(u)
+}
+
+// rectangles transforms the observations into the boxes of uniforms
+// they correspond to: the discrete coordinates become interval-censored
+// ones (F(x-), F(x)] while the continuous ones are transformed by their cdf
+func (jd *JointDistribution) rectangles(X *mat.Dense) (*mat.Dense, *Censoring, error) {
+	n, d := X.Dims()
+	if len(jd.Margins) != d {
+		return nil, nil, fmt.Errorf("%d margins are given while the observations have %d columns", len(jd.Margins), d)
+	}
+	U := mat.NewDense(n, d, nil)
+	cens := &Censoring{Status: mat.NewDense(n, d, nil), Upper: mat.NewDense(n, d, nil)}
+	for j, m := range jd.Margins {
+		dm, discrete := m.(DiscreteMargin)
+		for i := 0; i < n; i++ {
+			x := X.At(i, j)
+			u := m.CDF(x)
+			if discrete {
+				U.Set(i, j, dm.LeftLimit(x))
+				cens.Status.Set(i, j, IntervalCensored)
+				cens.Upper.Set(i, j, u)
+			} else {
+				U.Set(i, j, u)
+			}
+		}
+	}
+	return U, cens, nil
+}
+
+// LogLikelihood computes the copula part of the log-likelihood of the
+// observations (the marginal log-densities do not depend on the copula).
+// The discrete coordinates contribute through the C-volume of the
+// rectangle (F(x-), F(x)].
+func (jd *JointDistribution) LogLikelihood(X *mat.Dense) (float64, error) {
+	U, cens, err := jd.rectangles(X)
+	if err != nil {
+		return 0., err
+	}
+	return jd.Copula.CensoredLogLikelihood(U, cens)
+}
+
+// Fit estimates the parameter of the copula by maximizing the likelihood
+// of the observations given the (already fitted) margins. The discrete
+// margins are handled through rectangle probabilities so the Censoring
+// field of the options is overridden. The copula is updated.
+func (jd *JointDistribution) Fit(X *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	U, cens, err := jd.rectangles(X)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = DefaultFitOptions()
+	}
+	o := *opts
+	o.Censoring = cens
+	return jd.Copula.FitWithOptions(U, &o)
+}
+
+// Jitter returns the continuous extension of the observations: every
+// discrete coordinate is replaced by F(x-) + V (F(x) - F(x-)) where V is
+// uniform on [0, 1], the continuous ones are transformed by their cdf
+func (jd *JointDistribution) Jitter(X *mat.Dense, seed int64) (*mat.Dense, error) {
+	U, cens, err := jd.rectangles(X)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	n, d := U.Dims()
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			if cens.Status.At(i, j) == IntervalCensored {
+				low := U.At(i, j)
+				U.Set(i, j, low+rng.Float64()*(cens.Upper.At(i, j)-low))
+			}
+		}
+	}
+	return U, nil
+}
+
+// FitJittered estimates the parameter of the copula on the continuous
+// extension of the observations (see Jitter). It is cheaper than Fit
+// but the dependence is attenuated when the atoms are large.
+// The copula is updated.
+func (jd *JointDistribution) FitJittered(X *mat.Dense, opts *FitOptions, seed int64) (*FitResult, error) {
+	U, err := jd.Jitter(X, seed)
+	if err != nil {
+		return nil, err
+	}
+	return jd.Copula.FitWithOptions(U, opts)
+}
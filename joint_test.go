@@ -0,0 +1,111 @@
+// joint_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitJoint(t *testing.T) {
+	title("Joint distribution")
+}
+
+// poissonQuantile returns the smallest k such that P(X <= k) >= p
+func poissonQuantile(m *PoissonMargin, p float64) float64 {
+	k := 0.
+	for m.CDF(k) < p {
+		k++
+	}
+	return k
+}
+
+// loadCountObservations transforms the clayton sample into two count
+// columns and a gaussian one
+func loadCountObservations(t *testing.T, n int) (*mat.Dense, []Margin) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := NewPoissonMargin(2.), NewPoissonMargin(5.)
+	third := NewNormalMargin(0., 1.)
+	X := mat.NewDense(n, 3, nil)
+	for i := 0; i < n; i++ {
+		X.Set(i, 0, poissonQuantile(first, M.At(i, 0)))
+		X.Set(i, 1, poissonQuantile(second, M.At(i, 1)))
+		X.Set(i, 2, third.Quantile(M.At(i, 2)))
+	}
+	return X, []Margin{first, second, third}
+}
+
+func TestJointLogLikelihood(t *testing.T) {
+	checkTitle("Checking continuous joint likelihood...")
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	S := M.Slice(0, 200, 0, 2).(*mat.Dense)
+	uniform := NewNormalMargin(0., 1.)
+	X := mat.NewDense(200, 2, nil)
+	for i := 0; i < 200; i++ {
+		X.Set(i, 0, uniform.Quantile(S.At(i, 0)))
+		X.Set(i, 1, uniform.Quantile(S.At(i, 1)))
+	}
+	jd := NewJointDistribution(NewCopula("clayton", 2.), []Margin{uniform, uniform})
+	ll, err := jd.LogLikelihood(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := jd.Copula.LogLikelihood(S); math.Abs(ll-expected) > 1e-6*math.Abs(expected) {
+		t.Errorf("Bad likelihood, expected %f, got %f", expected, ll)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking margins dimension...")
+	jd.Margins = jd.Margins[:1]
+	if _, err := jd.LogLikelihood(X); err == nil {
+		t.Errorf("The margins should not match the observations")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestJointFit(t *testing.T) {
+	X, margins := loadCountObservations(t, 1000)
+
+	checkTitle("Checking discrete fit...")
+	jd := NewJointDistribution(NewCopula("clayton", 1.), margins)
+	opts := DefaultFitOptions()
+	opts.Profile = false
+	result, err := jd.Fit(X, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Theta-2.10) > 0.3 {
+		t.Errorf("Bad discrete fit, expected theta* = 2.10, got %f", result.Theta)
+		testERROR()
+		fmt.Println(result)
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking jittered fit...")
+	jittered, err := jd.FitJittered(X, opts, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// jittering attenuates the dependence
+	if !(jittered.Theta > 0.) || jittered.Theta > result.Theta+0.3 {
+		t.Errorf("Bad jittered fit, expected 0 < theta* < %f, got %f", result.Theta+0.3, jittered.Theta)
+		testERROR()
+		fmt.Println(jittered)
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,167 @@
+// kde.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// KernelType is the name of a kernel density estimator on the unit cube
+type KernelType string
+
+const (
+	// BetaKernel smooths every observation with a beta kernel whose
+	// shape depends on the evaluation point (no boundary bias)
+	BetaKernel KernelType = "beta"
+	// ProbitKernel estimates the density of the probit-transformed
+	// observations with a gaussian kernel and transforms it back
+	ProbitKernel KernelType = "probit"
+)
+
+// KernelDensity is a kernel estimator of a copula density
+type KernelDensity struct {
+	// U are the observations (uniforms or pseudo-observations)
+	U *mat.Dense
+	// Kernel is the kind of estimator
+	Kernel KernelType
+	// Bandwidth is the smoothing parameter
+	Bandwidth float64
+	// probit-transformed observations (probit kernel only)
+	probit *mat.Dense
+}
+
+// NewKernelDensity returns the kernel estimator of the copula density of
+// the observations U (uniforms or pseudo-observations). The bandwidth is
+// selected by likelihood cross-validation when it is not positive.
+func NewKernelDensity(U *mat.Dense, kernel KernelType, bandwidth float64) (*KernelDensity, error) {
+	n, d := U.Dims()
+	if n < 2 {
+		return nil, fmt.Errorf("At least 2 observations are required (got %d)", n)
+	}
+	kd := &KernelDensity{U: U, Kernel: kernel, Bandwidth: bandwidth}
+	switch kernel {
+	case BetaKernel:
+	case ProbitKernel:
+		kd.probit = mat.NewDense(n, d, nil)
+		kd.probit.Apply(func(i, j int, v float64) float64 {
+			return distuv.UnitNormal.Quantile(v)
+		}, U)
+	default:
+		return nil, fmt.Errorf("Unknown kernel '%s'", kernel)
+	}
+	if bandwidth <= 0. {
+		kd.Bandwidth = kd.crossValidation()
+	}
+	return kd, nil
+}
+
+// betaTerm computes the beta kernel of the i-th observation at u
+func (kd *KernelDensity) betaTerm(i int, u []float64, b float64) float64 {
+	p := 1.
+	for j, x := range kd.U.RawRowView(i) {
+		beta := distuv.Beta{Alpha: u[j]/b + 1., Beta: (1.-u[j])/b + 1.}
+		p *= beta.Prob(x)
+	}
+	return p
+}
+
+// probitTerm computes the gaussian kernel of the i-th transformed
+// observation at s
+func (kd *KernelDensity) probitTerm(i int, s []float64, h float64) float64 {
+	p := 1.
+	for j, x := range kd.probit.RawRowView(i) {
+		z := (s[j] - x) / h
+		p *= math.Exp(-0.5*z*z) / (math.Sqrt(2.*math.Pi) * h)
+	}
+	return p
+}
+
+// density computes the estimator at u with the given bandwidth,
+// leaving the observation skip out (none when skip < 0)
+func (kd *KernelDensity) density(u []float64, bandwidth float64, skip int) float64 {
+	n, _ := kd.U.Dims()
+	count := n
+	if skip >= 0 {
+		count--
+	}
+	s := 0.
+	switch kd.Kernel {
+	case ProbitKernel:
+		z := make([]float64, len(u))
+		jacobian := 1.
+		for j, x := range u {
+			z[j] = distuv.UnitNormal.Quantile(x)
+			jacobian *= distuv.UnitNormal.Prob(z[j])
+		}
+		for i := 0; i < n; i++ {
+			if i != skip {
+				s += kd.probitTerm(i, z, bandwidth)
+			}
+		}
+		return s / (float64(count) * jacobian)
+	default:
+		for i := 0; i < n; i++ {
+			if i != skip {
+				s += kd.betaTerm(i, u, bandwidth)
+			}
+		}
+		return s / float64(count)
+	}
+}
+
+// crossValidation returns the bandwidth maximizing the leave-one-out
+// log-likelihood of the observations
+func (kd *KernelDensity) crossValidation() float64 {
+	n, _ := kd.U.Dims()
+	fun := func(logh float64, args interface{}) float64 {
+		h := math.Exp(logh)
+		ll := 0.
+		for i := 0; i < n; i++ {
+			ll += math.Log(kd.density(kd.U.RawRowView(i), h, i))
+		}
+		if math.IsNaN(ll) {
+			return math.Inf(1)
+		}
+		return -ll
+	}
+	logh, _, _, err := BrentMinimizer(fun, nil, math.Log(1e-3), math.Log(2.), 1e-4)
+	if err != nil {
+		return 0.1
+	}
+	return math.Exp(logh)
+}
+
+// Pdf computes the estimated density at the given point
+func (kd *KernelDensity) Pdf(vector []float64) float64 {
+	for _, u := range vector {
+		if u <= 0. || u >= 1. {
+			return 0.
+		}
+	}
+	return kd.density(vector, kd.Bandwidth, -1)
+}
+
+// LogPdf computes the log of the estimated density at the given point
+func (kd *KernelDensity) LogPdf(vector []float64) float64 {
+	return math.Log(kd.Pdf(vector))
+}
+
+// PdfGrid evaluates a bivariate density (e.g. the Pdf method of a fitted
+// copula or of a kernel estimator) on the midpoints of a m x m grid of
+// the unit square: the (a, b) entry is pdf((a+0.5)/m, (b+0.5)/m)
+func PdfGrid(pdf func(vector []float64) float64, m int) *mat.Dense {
+	G := mat.NewDense(m, m, nil)
+	vector := make([]float64, 2)
+	for a := 0; a < m; a++ {
+		for b := 0; b < m; b++ {
+			vector[0] = (float64(a) + 0.5) / float64(m)
+			vector[1] = (float64(b) + 0.5) / float64(m)
+			G.Set(a, b, pdf(vector))
+		}
+	}
+	return G
+}
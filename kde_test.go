@@ -0,0 +1,66 @@
+// kde_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitKDE(t *testing.T) {
+	title("Kernel density estimation")
+}
+
+func TestKernelDensity(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	U := PseudoObservations(M.Slice(0, 300, 0, 2).(*mat.Dense))
+	clayton := NewCopula("clayton", 2.1)
+
+	for _, kernel := range []KernelType{BetaKernel, ProbitKernel} {
+		checkTitle(fmt.Sprintf("Checking %s kernel density...", kernel))
+		kd, err := NewKernelDensity(U, kernel, 0.)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !(kd.Bandwidth > 1e-3 && kd.Bandwidth < 2.) {
+			t.Errorf("Bad cross-validated bandwidth (%f)", kd.Bandwidth)
+			testERROR()
+			continue
+		}
+		// the estimate integrates to 1 and is close to the true density
+		m := 40
+		G := PdfGrid(kd.Pdf, m)
+		T := PdfGrid(clayton.Pdf, m)
+		integral := mat.Sum(G) / float64(m*m)
+		if math.Abs(integral-1.) > 0.05 {
+			t.Errorf("Bad kernel density, expected an integral of 1, got %f", integral)
+			testERROR()
+			continue
+		}
+		if kd.Pdf([]float64{0.1, 0.1}) < kd.Pdf([]float64{0.1, 0.9}) {
+			t.Errorf("The density should be greater at the lower tail")
+			testERROR()
+			continue
+		}
+		if p, q := kd.Pdf([]float64{0.5, 0.5}), T.At(m/2, m/2); math.Abs(p-q) > 0.5 {
+			t.Errorf("Bad kernel density at the center, expected %f, got %f", q, p)
+			testERROR()
+			continue
+		}
+		testOK()
+	}
+
+	checkTitle("Checking unknown kernel...")
+	if _, err := NewKernelDensity(U, KernelType("gaussian"), 0.1); err == nil {
+		t.Errorf("The kernel should be unknown")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,99 @@
+// kdtree.go
+
+package gopula
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// leafSize is the maximum number of points of a leaf of the k-d tree
+const leafSize = 16
+
+// kdNode is a node of a k-d tree. It stores the bounding box
+// of its points to prune the dominance queries.
+type kdNode struct {
+	low, high   []float64
+	count       int
+	points      [][]float64 // only for the leaves
+	left, right *kdNode
+}
+
+// kdTree is a k-d tree counting the points lower than a given
+// point (componentwise)
+type kdTree struct {
+	root *kdNode
+	dim  int
+}
+
+// newKdTree builds the k-d tree of the rows of M
+func newKdTree(M *mat.Dense) *kdTree {
+	n, d := M.Dims()
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = M.RawRowView(i)
+	}
+	return &kdTree{root: buildKdNode(points, d, 0), dim: d}
+}
+
+// buildKdNode recursively splits the points at the median
+// of the coordinate given by the depth
+func buildKdNode(points [][]float64, d int, depth int) *kdNode {
+	node := &kdNode{low: make([]float64, d), high: make([]float64, d), count: len(points)}
+	for j := 0; j < d; j++ {
+		node.low[j], node.high[j] = points[0][j], points[0][j]
+		for _, p := range points[1:] {
+			if p[j] < node.low[j] {
+				node.low[j] = p[j]
+			} else if p[j] > node.high[j] {
+				node.high[j] = p[j]
+			}
+		}
+	}
+	if len(points) <= leafSize {
+		node.points = points
+		return node
+	}
+	axis := depth % d
+	sort.Slice(points, func(a, b int) bool { return points[a][axis] < points[b][axis] })
+	half := len(points) / 2
+	node.left = buildKdNode(points[:half], d, depth+1)
+	node.right = buildKdNode(points[half:], d, depth+1)
+	return node
+}
+
+// countLower returns the number of points p such that p <= u (componentwise)
+func (tree *kdTree) countLower(u []float64) int {
+	return tree.root.countLower(u)
+}
+
+func (node *kdNode) countLower(u []float64) int {
+	all := true
+	for j, x := range u {
+		if node.low[j] > x {
+			return 0
+		}
+		all = all && node.high[j] <= x
+	}
+	if all {
+		return node.count
+	}
+	if node.points == nil {
+		return node.left.countLower(u) + node.right.countLower(u)
+	}
+	count := 0
+	for _, p := range node.points {
+		lower := true
+		for j, x := range u {
+			if p[j] > x {
+				lower = false
+				break
+			}
+		}
+		if lower {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,34 @@
+// kdtree_test.go
+
+package gopula
+
+import (
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestKdTree(t *testing.T) {
+	checkTitle("Checking k-d tree counts...")
+	rng := rand.New(rand.NewSource(1))
+	M := mat.NewDense(500, 3, nil)
+	M.Apply(func(i, j int, v float64) float64 { return rng.Float64() }, M)
+	tree := newKdTree(mat.DenseCopyOf(M))
+	for q := 0; q < 50; q++ {
+		u := []float64{rng.Float64(), rng.Float64(), rng.Float64()}
+		expected := 0
+		for i := 0; i < 500; i++ {
+			row := M.RawRowView(i)
+			if row[0] <= u[0] && row[1] <= u[1] && row[2] <= u[2] {
+				expected++
+			}
+		}
+		if count := tree.countLower(u); count != expected {
+			t.Errorf("Bad count at %v, expected %d, got %d", u, expected, count)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
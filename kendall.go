@@ -0,0 +1,192 @@
+// kendall.go
+
+package gopula
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// KendallCdf computes the Kendall distribution function K(t) = P(C(U) <= t)
+// of the copula in dimension dim. For an archimedean copula
+// K(t) = sum_k (-phi(t))^k psi^(k)(phi(t)) / k! where k ranges from 0 to
+// dim-1 and phi is the inverse of the generator.
+func (arch *ArchimedeanCopula) KendallCdf(t float64, dim int) float64 {
+	if t <= 0. {
+		return 0.
+	}
+	if t >= 1. {
+		return 1.
+	}
+	x := arch.copula.PsiInv(t, arch.theta)
+	k := t
+	f := 1.
+	for i := 1; i < dim; i++ {
+		f *= -x / float64(i)
+		k += f * arch.copula.PsiD(i, x, arch.theta)
+	}
+	return math.Min(k, 1.)
+}
+
+// KendallPpf computes the quantile function of the Kendall distribution
+func (arch *ArchimedeanCopula) KendallPpf(p float64, dim int) float64 {
+	if p <= 0. {
+		return 0.
+	}
+	if p >= 1. {
+		return 1.
+	}
+	fun := func(t float64, args interface{}) float64 {
+		return arch.KendallCdf(t, dim) - p
+	}
+	t, err := BrentRootFinder(fun, nil, 0., 1., 1e-12)
+	if err != nil {
+		return math.NaN()
+	}
+	return t
+}
+
+// KendallProcess is the empirical Kendall distribution of a sample
+type KendallProcess struct {
+	// W are the sorted Kendall pseudo-observations: W_i is the
+	// proportion of the other observations lower than the i-th one
+	// (componentwise)
+	W []float64
+}
+
+// NewKendallProcess computes the Kendall pseudo-observations of M
+func NewKendallProcess(M *mat.Dense) *KendallProcess {
+	n, d := M.Dims()
+	W := make([]float64, n)
+	for i := 0; i < n; i++ {
+		xi := M.RawRowView(i)
+		count := 0
+		for k := 0; k < n; k++ {
+			if k == i {
+				continue
+			}
+			xk := M.RawRowView(k)
+			lower := true
+			for j := 0; j < d && lower; j++ {
+				lower = xk[j] < xi[j]
+			}
+			if lower {
+				count++
+			}
+		}
+		W[i] = float64(count) / float64(n-1)
+	}
+	sort.Float64s(W)
+	return &KendallProcess{W: W}
+}
+
+// CDF computes the empirical Kendall distribution function K_n(t)
+func (kp *KendallProcess) CDF(t float64) float64 {
+	k := sort.Search(len(kp.W), func(i int) bool { return kp.W[i] > t })
+	return float64(k) / float64(len(kp.W))
+}
+
+// KendallPlot returns the data of the Kendall plot: the first column
+// gathers the expected quantiles of the Kendall distribution of the
+// copula, the second one the sorted Kendall pseudo-observations of M
+func (arch *ArchimedeanCopula) KendallPlot(M *mat.Dense) *mat.Dense {
+	n, d := M.Dims()
+	kp := NewKendallProcess(M)
+	P := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		P.Set(i, 0, arch.KendallPpf(float64(i+1)/float64(n+1), d))
+		P.Set(i, 1, kp.W[i])
+	}
+	return P
+}
+
+// TestResult details the output of a statistical test
+type TestResult struct {
+	// Statistic is the value of the test statistic
+	Statistic float64
+	// PValue is the (approximate) p-value of the test
+	PValue float64
+	// Replicates is the distribution of the statistic under the
+	// null hypothesis (empty for closed-form tests)
+	Replicates []float64
+}
+
+// kendallCvM computes the Cramér-von Mises distance between the empirical
+// Kendall distribution and the one of the copula:
+// S_n = n ∫ (K_n(t) - K(t))² dK(t) (Genest, Quessy and Rémillard, 2006)
+func (arch *ArchimedeanCopula) kendallCvM(kp *KendallProcess, dim int) float64 {
+	n := len(kp.W)
+	nF := float64(n)
+	s := nF / 3.
+	prev := arch.KendallCdf(kp.W[0], dim)
+	for j := 1; j < n; j++ {
+		next := arch.KendallCdf(kp.W[j], dim)
+		kn := float64(j) / nF
+		s += nF * (kn*kn*(next-prev) - kn*(next*next-prev*prev))
+		prev = next
+	}
+	// K_n is 1 beyond the last pseudo-observation
+	return s + nF*(prev*prev-prev)
+}
+
+// KendallGoF tests whether the observations (uniforms or pseudo-
+// observations) come from the family of the copula. The statistic is the
+// Cramér-von Mises distance between the empirical Kendall distribution
+// and the one of the fitted copula, the p-value is computed through a
+// parametric bootstrap (opts.Replicates samples of the fitted copula,
+// every one being refitted). The receiver is not modified.
+func (arch *ArchimedeanCopula) KendallGoF(M *mat.Dense, opts *BootstrapOptions) (*TestResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if n < 2 {
+		return nil, errors.New("At least 2 observations are required")
+	}
+	cop := &ArchimedeanCopula{theta: arch.theta, copula: arch.copula}
+	theta, _, _, _, err := cop.maximizeLikelihood(M)
+	if err != nil {
+		return nil, err
+	}
+	cop.theta = theta
+	statistic := cop.kendallCvM(NewKendallProcess(M), d)
+
+	replicates := make([]float64, opts.Replicates)
+	parallelize(opts.Replicates, opts.Workers, func(b int) {
+		rng := rand.New(rand.NewSource(opts.Seed + int64(b)))
+		S := cop.sampleWith(n, d, rng)
+		fitted := &ArchimedeanCopula{theta: theta, copula: arch.copula}
+		thetaB, _, _, _, err := fitted.maximizeLikelihood(S)
+		if err != nil || math.IsNaN(thetaB) {
+			replicates[b] = math.NaN()
+			return
+		}
+		fitted.theta = thetaB
+		replicates[b] = fitted.kendallCvM(NewKendallProcess(S), d)
+	})
+	replicates = removeNaN(replicates)
+	if len(replicates) == 0 {
+		return nil, ErrNoReplicate
+	}
+	return &TestResult{
+		Statistic:  statistic,
+		PValue:     bootstrapPValue(statistic, replicates),
+		Replicates: replicates,
+	}, nil
+}
+
+// bootstrapPValue computes the proportion of the replicates
+// greater than the statistic (with the usual 1/2 correction)
+func bootstrapPValue(statistic float64, replicates []float64) float64 {
+	count := 0.5
+	for _, r := range replicates {
+		if r > statistic {
+			count++
+		}
+	}
+	return count / float64(len(replicates)+1)
+}
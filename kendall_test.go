@@ -0,0 +1,103 @@
+// kendall_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitKendall(t *testing.T) {
+	title("Kendall distribution")
+}
+
+func TestKendallCdf(t *testing.T) {
+	checkTitle("Checking Kendall cdf...")
+	theta := 2.
+	AC := NewCopula("clayton", theta)
+	for _, x := range []float64{0.01, 0.2, 0.5, 0.9} {
+		// closed form of the bivariate clayton copula
+		expected := x + x*(1.-math.Pow(x, theta))/theta
+		if k := AC.KendallCdf(x, 2); math.Abs(k-expected) > 1e-9 {
+			t.Errorf("Bad Kendall cdf at %f, expected %f, got %f", x, expected, k)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+
+	checkTitle("Checking Kendall ppf...")
+	for _, family := range []string{"clayton", "frank", "joe", "amh", "gumbel"} {
+		cop := NewCopula(family, -1.)
+		for _, p := range []float64{0.1, 0.5, 0.9} {
+			if k := cop.KendallCdf(cop.KendallPpf(p, 3), 3); math.Abs(k-p) > 1e-6 {
+				t.Errorf("Bad Kendall ppf (%s), expected %f, got %f", family, p, k)
+				testERROR()
+				return
+			}
+		}
+	}
+	testOK()
+}
+
+func TestKendallProcess(t *testing.T) {
+	checkTitle("Checking Kendall process...")
+	M := mat.NewDense(4, 2, []float64{
+		0.1, 0.2,
+		0.4, 0.3,
+		0.3, 0.9,
+		0.8, 0.7,
+	})
+	kp := NewKendallProcess(M)
+	// 0, 1/3, 1/3 and 2/3 of the other points are lower
+	expected := []float64{0., 1. / 3., 1. / 3., 2. / 3.}
+	for i, w := range expected {
+		if math.Abs(kp.W[i]-w) > 1e-12 {
+			t.Errorf("Bad pseudo-observations, expected %v, got %v", expected, kp.W)
+			testERROR()
+			return
+		}
+	}
+	if kp.CDF(0.5) != 0.75 {
+		t.Errorf("Bad empirical Kendall cdf, expected 0.75, got %f", kp.CDF(0.5))
+		testERROR()
+		return
+	}
+	testOK()
+}
+
+func TestKendallGoF(t *testing.T) {
+	S := NewCopula("clayton", 2.1).sampleWith(300, 3, rand.New(rand.NewSource(10)))
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 50
+
+	checkTitle("Checking Kendall goodness-of-fit (clayton)...")
+	result, err := NewCopula("clayton", 1.).KendallGoF(S, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 {
+		t.Errorf("The clayton copula should not be rejected (p = %f)", result.PValue)
+		testERROR()
+		fmt.Println(result.Statistic)
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking Kendall goodness-of-fit (gumbel)...")
+	result, err = NewCopula("gumbel", 2.).KendallGoF(S, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 0.05 {
+		t.Errorf("The gumbel copula should be rejected (p = %f)", result.PValue)
+		testERROR()
+		fmt.Println(result.Statistic)
+	} else {
+		testOK()
+	}
+}
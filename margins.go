@@ -0,0 +1,196 @@
+// margins.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Margin is an interface to implement a univariate distribution
+// transforming observations into uniforms (the distuv distributions
+// satisfy it)
+type Margin interface {
+	CDF(x float64) float64
+}
+
+// ParametricMargin is an interface to implement a margin whose
+// parameters can be estimated by maximum likelihood
+type ParametricMargin interface {
+	Margin
+	LogProb(x float64) float64
+	Fit(x []float64)
+	Params() []float64
+	SetParams(params []float64) error
+}
+
+// ECDF is the empirical cumulative distribution function of a sample.
+// It is rescaled by n/(n+1) so that its values lie in (0, 1).
+type ECDF struct {
+	sorted []float64
+}
+
+// NewECDF returns the empirical cdf of the given sample
+func NewECDF(x []float64) *ECDF {
+	sorted := createCopy(x)
+	sort.Float64s(sorted)
+	return &ECDF{sorted: sorted}
+}
+
+// CDF computes the rescaled proportion of the sample lower than or equal to x
+func (e *ECDF) CDF(x float64) float64 {
+	// index of the first value greater than x
+	k := sort.Search(len(e.sorted), func(i int) bool { return e.sorted[i] > x })
+	return float64(k) / float64(len(e.sorted)+1)
+}
+
+// PseudoObservations transforms every column of X with its
+// rescaled empirical cdf (i.e. rank / (n+1))
+func PseudoObservations(X *mat.Dense) *mat.Dense {
+	n, p := X.Dims()
+	U := mat.NewDense(n, p, nil)
+	for j := 0; j < p; j++ {
+		col := rawCol(X, j)
+		ecdf := NewECDF(col)
+		for i := 0; i < n; i++ {
+			U.Set(i, j, ecdf.CDF(col[i]))
+		}
+	}
+	return U
+}
+
+// applyMargins transforms every column of X with the corresponding margin
+func applyMargins(X *mat.Dense, margins []Margin) (*mat.Dense, error) {
+	n, p := X.Dims()
+	if len(margins) != p {
+		return nil, fmt.Errorf("%d margins are given while the observations have %d columns", len(margins), p)
+	}
+	U := mat.NewDense(n, p, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			U.Set(i, j, margins[j].CDF(X.At(i, j)))
+		}
+	}
+	return U, nil
+}
+
+// NormalMargin is a gaussian margin
+type NormalMargin struct {
+	distuv.Normal
+}
+
+// NewNormalMargin returns a gaussian margin with given mean and standard deviation
+func NewNormalMargin(mu float64, sigma float64) *NormalMargin {
+	return &NormalMargin{distuv.Normal{Mu: mu, Sigma: sigma}}
+}
+
+// Fit estimates the mean and the standard deviation by maximum likelihood
+func (m *NormalMargin) Fit(x []float64) {
+	mu, variance := stat.PopMeanVariance(x, nil)
+	m.Mu = mu
+	m.Sigma = math.Sqrt(variance)
+}
+
+// Params returns the vector [mean, standard deviation]
+func (m *NormalMargin) Params() []float64 {
+	return []float64{m.Mu, m.Sigma}
+}
+
+// SetParams updates the mean and the standard deviation
+func (m *NormalMargin) SetParams(params []float64) error {
+	if len(params) != 2 {
+		return fmt.Errorf("Normal margin has 2 parameters (got %d)", len(params))
+	}
+	if params[1] <= 0. {
+		return fmt.Errorf("The standard deviation must be positive (got %f)", params[1])
+	}
+	m.Mu, m.Sigma = params[0], params[1]
+	return nil
+}
+
+// ExponentialMargin is an exponential margin
+type ExponentialMargin struct {
+	distuv.Exponential
+}
+
+// NewExponentialMargin returns an exponential margin with given rate
+func NewExponentialMargin(rate float64) *ExponentialMargin {
+	return &ExponentialMargin{distuv.Exponential{Rate: rate}}
+}
+
+// Fit estimates the rate by maximum likelihood
+func (m *ExponentialMargin) Fit(x []float64) {
+	m.Rate = 1. / mean(x)
+}
+
+// Params returns the vector [rate]
+func (m *ExponentialMargin) Params() []float64 {
+	return []float64{m.Rate}
+}
+
+// SetParams updates the rate
+func (m *ExponentialMargin) SetParams(params []float64) error {
+	if len(params) != 1 {
+		return fmt.Errorf("Exponential margin has 1 parameter (got %d)", len(params))
+	}
+	if params[0] <= 0. {
+		return fmt.Errorf("The rate must be positive (got %f)", params[0])
+	}
+	m.Rate = params[0]
+	return nil
+}
+
+// DiscreteMargin is an interface to implement a margin with atoms
+// (count data). An observation x then corresponds to the interval
+// (LeftLimit(x), CDF(x)] of uniforms.
+type DiscreteMargin interface {
+	Margin
+	// LeftLimit returns P(X < x)
+	LeftLimit(x float64) float64
+}
+
+// PoissonMargin is a Poisson margin
+type PoissonMargin struct {
+	distuv.Poisson
+}
+
+// NewPoissonMargin returns a Poisson margin with given mean
+func NewPoissonMargin(lambda float64) *PoissonMargin {
+	return &PoissonMargin{distuv.Poisson{Lambda: lambda}}
+}
+
+// LeftLimit returns P(X < x)
+func (m *PoissonMargin) LeftLimit(x float64) float64 {
+	k := math.Ceil(x) - 1.
+	if k < 0. {
+		return 0.
+	}
+	return m.CDF(k)
+}
+
+// Fit estimates the mean by maximum likelihood
+func (m *PoissonMargin) Fit(x []float64) {
+	m.Lambda = mean(x)
+}
+
+// Params returns the vector [mean]
+func (m *PoissonMargin) Params() []float64 {
+	return []float64{m.Lambda}
+}
+
+// SetParams updates the mean
+func (m *PoissonMargin) SetParams(params []float64) error {
+	if len(params) != 1 {
+		return fmt.Errorf("Poisson margin has 1 parameter (got %d)", len(params))
+	}
+	if params[0] <= 0. {
+		return fmt.Errorf("The mean must be positive (got %f)", params[0])
+	}
+	m.Lambda = params[0]
+	return nil
+}
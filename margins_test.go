@@ -0,0 +1,86 @@
+// margins_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitMargins(t *testing.T) {
+	title("Margins")
+}
+
+func TestECDF(t *testing.T) {
+	checkTitle("Checking ecdf...")
+	ecdf := NewECDF([]float64{3., 1., 2., 2.})
+	expected := map[float64]float64{0.: 0., 1.: 0.2, 1.5: 0.2, 2.: 0.6, 3.: 0.8, 10.: 0.8}
+	for x, p := range expected {
+		if math.Abs(ecdf.CDF(x)-p) > 1e-12 {
+			t.Errorf("Bad ecdf at %f, expected %f, got %f", x, p, ecdf.CDF(x))
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
+
+func TestPseudoObservations(t *testing.T) {
+	checkTitle("Checking pseudo-observations...")
+	X := mat.NewDense(4, 2, []float64{
+		10., -1.,
+		30., -3.,
+		20., -4.,
+		40., -2.,
+	})
+	U := PseudoObservations(X)
+	expected := mat.NewDense(4, 2, []float64{
+		0.2, 0.8,
+		0.6, 0.4,
+		0.4, 0.2,
+		0.8, 0.6,
+	})
+	if !mat.EqualApprox(U, expected, 1e-12) {
+		t.Errorf("Bad pseudo-observations")
+		testERROR()
+		matPrint(U)
+	} else {
+		testOK()
+	}
+}
+
+func TestNormalMarginFit(t *testing.T) {
+	checkTitle("Checking normal margin fit...")
+	m := NewNormalMargin(0., 1.)
+	m.Fit([]float64{1., 2., 3., 4.})
+	if math.Abs(m.Mu-2.5) > 1e-12 || math.Abs(m.Sigma-math.Sqrt(1.25)) > 1e-12 {
+		t.Errorf("Bad normal fit, expected (2.5, 1.118), got (%f, %f)", m.Mu, m.Sigma)
+		testERROR()
+	} else if err := m.SetParams([]float64{0., -1.}); err == nil {
+		t.Errorf("Negative standard deviation should be rejected")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestPoissonMargin(t *testing.T) {
+	checkTitle("Checking Poisson margin...")
+	m := NewPoissonMargin(1.)
+	m.Fit([]float64{1., 2., 3., 6.})
+	if math.Abs(m.Lambda-3.) > 1e-12 {
+		t.Errorf("Bad Poisson fit, expected 3, got %f", m.Lambda)
+		testERROR()
+		return
+	}
+	for _, x := range []float64{0., 1., 4.} {
+		if p := m.CDF(x) - m.LeftLimit(x); math.Abs(p-m.Prob(x)) > 1e-12 {
+			t.Errorf("Bad atom at %f, expected %f, got %f", x, m.Prob(x), p)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+}
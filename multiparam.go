@@ -0,0 +1,265 @@
+// multiparam.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Parametric is an interface to implement a copula driven by
+// a vector of parameters. Multi-parameter families, mixtures
+// or elliptical copulas can then share the same fit pipeline (see FitN)
+type Parametric interface {
+	Family() string
+	Params() []float64
+	ParamBounds() ([]float64, []float64)
+	SetParams(params []float64) error
+	LogPdf(vector []float64) float64
+}
+
+// FitResultN is a basic structure detailing the output of the fit
+// of a copula with several parameters
+type FitResultN struct {
+	// Params is the estimated parameter vector
+	Params []float64
+	// LogLikelihood is the corresponding log-likelihood (the maximum)
+	LogLikelihood float64
+	// Covariance is the asymptotic covariance matrix of the estimator
+	// (inverse of the observed information)
+	Covariance *mat.SymDense
+	// StdErr are the standard errors of the parameters
+	StdErr []float64
+	// Correlation is the correlation matrix of the estimator
+	Correlation *mat.SymDense
+	// Evals is the number of function evaluations
+	Evals int
+	// Message describes whether the fit has suceeded
+	Message string
+}
+
+func (fr *FitResultN) String() string {
+	lines := []string{fmt.Sprintf("%8s %.6f", "ℓ", fr.LogLikelihood)}
+	for i, p := range fr.Params {
+		lines = append(lines, fmt.Sprintf("%8s %.6f (± %.6f)", fmt.Sprintf("𝜃[%d]", i), p, fr.StdErr[i]))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%8s %d", "Evals", fr.Evals),
+		fmt.Sprintf("%8s %s", "Message", fr.Message))
+	return strings.Join(lines, "\n")
+}
+
+// Params returns the parameter vector of the copula (here [𝜃])
+func (arch *ArchimedeanCopula) Params() []float64 {
+	return []float64{arch.theta}
+}
+
+// ParamBounds returns the range where every parameter is well defined
+func (arch *ArchimedeanCopula) ParamBounds() ([]float64, []float64) {
+	a, b := arch.copula.ThetaBounds()
+	return []float64{a}, []float64{b}
+}
+
+// SetParams updates the parameter vector of the copula (here [𝜃])
+func (arch *ArchimedeanCopula) SetParams(params []float64) error {
+	if len(params) != 1 {
+		return fmt.Errorf("%s copula has 1 parameter (got %d)", arch.Family(), len(params))
+	}
+	a, b := arch.copula.ThetaBounds()
+	if params[0] < a || params[0] > b {
+		return fmt.Errorf("𝜃 = %f is out of bounds [%f, %f]", params[0], a, b)
+	}
+	arch.theta = params[0]
+	return nil
+}
+
+// paramsLogLikelihood computes the log-likelihood of a batch of
+// observations given the current parameters of the copula
+func paramsLogLikelihood(cop Parametric, M *mat.Dense) float64 {
+	nObs, _ := M.Dims()
+	ll := 0.
+	for i := 0; i < nObs; i++ {
+		lpdf := cop.LogPdf(M.RawRowView(i))
+		if !math.IsNaN(lpdf) {
+			ll += lpdf
+		}
+	}
+	return ll
+}
+
+// toUnbounded maps a parameter lying in (a, b) to the real line: the
+// identity when both bounds are infinite, log(x - a) or log(b - x) when
+// only one of them is and the logit of (x - a) / (b - a) otherwise
+func toUnbounded(x, a, b float64) float64 {
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		return x
+	case math.IsInf(b, 1):
+		return math.Log(x - a)
+	case math.IsInf(a, -1):
+		return math.Log(b - x)
+	}
+	p := (x - a) / (b - a)
+	return math.Log(p / (1. - p))
+}
+
+// toBounded maps a real value to the interval (a, b)
+// (inverse of toUnbounded)
+func toBounded(y, a, b float64) float64 {
+	switch {
+	case math.IsInf(a, -1) && math.IsInf(b, 1):
+		return y
+	case math.IsInf(b, 1):
+		return a + math.Exp(y)
+	case math.IsInf(a, -1):
+		return b - math.Exp(y)
+	}
+	return a + (b-a)/(1.+math.Exp(-y))
+}
+
+// insideBounds moves x slightly inside the interval (a, b)
+func insideBounds(x, a, b float64) float64 {
+	width := b - a
+	if math.IsInf(width, 0) {
+		width = 1.
+	}
+	margin := 1e-3 * width
+	return math.Max(a+margin, math.Min(b-margin, x))
+}
+
+// FitN estimates the parameter vector of the copula through
+// maximum likelihood estimation according to the input observations.
+// The current parameters of the copula are used as starting point
+// and are updated with the estimated ones.
+func FitN(cop Parametric, M *mat.Dense) (*FitResultN, error) {
+	lower, upper := cop.ParamBounds()
+	x0 := append([]float64{}, cop.Params()...)
+	k := len(x0)
+
+	// the optimization is performed on an unbounded scale
+	y0 := make([]float64, k)
+	for i := 0; i < k; i++ {
+		// the starting point is slightly moved inside the domain
+		y0[i] = toUnbounded(insideBounds(x0[i], lower[i], upper[i]), lower[i], upper[i])
+	}
+
+	params := make([]float64, k)
+	negLogLikelihood := func(y []float64) float64 {
+		for i := 0; i < k; i++ {
+			params[i] = toBounded(y[i], lower[i], upper[i])
+		}
+		if err := cop.SetParams(params); err != nil {
+			return math.Inf(1)
+		}
+		return -paramsLogLikelihood(cop, M)
+	}
+
+	p := optimize.Problem{Func: negLogLikelihood}
+	s := optimize.Settings{FuncEvaluations: MaxFunEval}
+	result, err := optimize.Minimize(p, y0, &s, &optimize.NelderMead{})
+	if result == nil {
+		return nil, err
+	}
+
+	best := make([]float64, k)
+	finite := true
+	for i := 0; i < k; i++ {
+		best[i] = toBounded(result.X[i], lower[i], upper[i])
+		if math.IsNaN(best[i]) || math.IsInf(best[i], 0) {
+			finite = false
+		}
+	}
+
+	fr := &FitResultN{
+		Params:        best,
+		LogLikelihood: -result.F,
+		StdErr:        make([]float64, k),
+		Evals:         result.Stats.FuncEvaluations,
+		Message:       "Success",
+	}
+	for i := range fr.StdErr {
+		fr.StdErr[i] = math.NaN()
+	}
+	if !finite {
+		// the copula gets back its initial parameters
+		cop.SetParams(x0)
+		err = fmt.Errorf("The estimated parameters %v are not finite", best)
+		fr.Message = "Error: " + err.Error()
+		return fr, err
+	}
+	if errSet := cop.SetParams(best); errSet != nil {
+		return nil, errSet
+	}
+	if err != nil {
+		fr.Message = "Error: " + err.Error()
+	}
+
+	cov, errCov := paramsCovariance(cop, M, best)
+	if errCov != nil {
+		fr.Message += " (" + errCov.Error() + ")"
+		return fr, err
+	}
+	fr.Covariance = cov
+	for i := 0; i < k; i++ {
+		fr.StdErr[i] = math.Sqrt(cov.At(i, i))
+	}
+	fr.Correlation = mat.NewSymDense(k, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			fr.Correlation.SetSym(i, j, cov.At(i, j)/(fr.StdErr[i]*fr.StdErr[j]))
+		}
+	}
+	return fr, err
+}
+
+// paramsCovariance computes the inverse of the observed information
+// matrix (hessian of the negative log-likelihood) at the given parameters
+func paramsCovariance(cop Parametric, M *mat.Dense, best []float64) (*mat.SymDense, error) {
+	k := len(best)
+	tmp := make([]float64, k)
+	negLogLikelihood := func(x []float64) float64 {
+		copy(tmp, x)
+		if err := cop.SetParams(tmp); err != nil {
+			return math.NaN()
+		}
+		return -paramsLogLikelihood(cop, M)
+	}
+
+	step := math.Inf(1)
+	lower, upper := cop.ParamBounds()
+	for i := 0; i < k; i++ {
+		step = math.Min(step, 0.5*math.Min(best[i]-lower[i], upper[i]-best[i]))
+	}
+	if step <= 0. {
+		return nil, errors.New("the estimate lies on the boundary of the domain")
+	}
+	cov, err := hessianCovariance(negLogLikelihood, best, math.Min(1e-3, step))
+	// restore the best parameters
+	cop.SetParams(best)
+	return cov, err
+}
+
+// hessianCovariance inverts the hessian of the opposite of a
+// log-likelihood at the given parameters (finite differences
+// with the given step)
+func hessianCovariance(negLogLikelihood func(x []float64) float64, best []float64, step float64) (*mat.SymDense, error) {
+	k := len(best)
+	H := mat.NewSymDense(k, nil)
+	fd.Hessian(H, negLogLikelihood, best, &fd.Settings{Step: step})
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(H); !ok {
+		return nil, errors.New("the observed information is not positive definite")
+	}
+	cov := mat.NewSymDense(k, nil)
+	if err := chol.InverseTo(cov); err != nil {
+		return nil, err
+	}
+	return cov, nil
+}
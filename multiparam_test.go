@@ -0,0 +1,136 @@
+// multiparam_test.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestInitMultiParam(t *testing.T) {
+	title("Multi-parameter")
+}
+
+func TestArchimedeanParams(t *testing.T) {
+	checkTitle("Checking parameter vector...")
+	AC := NewCopula("gumbel", 3.)
+	if err := AC.SetParams([]float64{0.5}); err == nil {
+		t.Errorf("Out of bounds parameter should be rejected")
+		testERROR()
+		return
+	}
+	if err := AC.SetParams([]float64{2., 3.}); err == nil {
+		t.Errorf("Bad parameter vector length should be rejected")
+		testERROR()
+		return
+	}
+	if err := AC.SetParams([]float64{4.}); err != nil || AC.Params()[0] != 4. {
+		t.Errorf("Bad parameter update, expected 4.0, got %v", AC.Params())
+		testERROR()
+		return
+	}
+	testOK()
+}
+
+func TestFitN(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTitle("Checking vector-valued fit...")
+	AC := NewCopula("clayton", 1.)
+	result, err := FitN(AC, M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Params[0]-2.10) > 0.15 {
+		t.Errorf("Bad MLE fit, expected theta* = 2.10, got %f", result.Params[0])
+		testERROR()
+		fmt.Println(result)
+	} else if !(result.StdErr[0] > 0.) || math.Abs(result.Correlation.At(0, 0)-1.) > 1e-10 {
+		t.Errorf("Bad covariance estimation (stderr = %f)", result.StdErr[0])
+		testERROR()
+		fmt.Println(result)
+	} else {
+		testOK()
+	}
+}
+
+// halfLineClayton is a clayton copula whose parameter is only
+// bounded below
+type halfLineClayton struct {
+	*ArchimedeanCopula
+}
+
+func (c halfLineClayton) ParamBounds() ([]float64, []float64) {
+	return []float64{0.}, []float64{math.Inf(1)}
+}
+
+// logClayton is a clayton copula parametrized by log(𝜃)
+type logClayton struct {
+	*ArchimedeanCopula
+}
+
+func (c logClayton) Params() []float64 {
+	return []float64{math.Log(c.Theta())}
+}
+
+func (c logClayton) ParamBounds() ([]float64, []float64) {
+	return []float64{math.Inf(-1)}, []float64{math.Inf(1)}
+}
+
+func (c logClayton) SetParams(params []float64) error {
+	return c.ArchimedeanCopula.SetParams([]float64{math.Exp(params[0])})
+}
+
+// driftModel has a likelihood increasing without bound with its parameter
+type driftModel struct {
+	p float64
+}
+
+func (m *driftModel) Family() string    { return "drift" }
+func (m *driftModel) Params() []float64 { return []float64{m.p} }
+func (m *driftModel) ParamBounds() ([]float64, []float64) {
+	return []float64{0.}, []float64{math.Inf(1)}
+}
+func (m *driftModel) LogPdf(vector []float64) float64 { return m.p }
+func (m *driftModel) SetParams(params []float64) error {
+	m.p = params[0]
+	return nil
+}
+
+func TestFitNUnboundedParams(t *testing.T) {
+	M := NewCopula("clayton", 2.1).sampleWith(1000, 2, rand.New(rand.NewSource(14)))
+	ml := NewCopula("clayton", 1.).Fit(M)
+
+	checkTitle("Checking half-line parameter...")
+	result, err := FitN(halfLineClayton{NewCopula("clayton", 1.)}, M)
+	if err != nil || math.Abs(result.Params[0]-ml.Theta) > 1e-3 || !(result.StdErr[0] > 0.) {
+		t.Errorf("Bad fit on (0, +Inf), expected %f, got %v (%v)", ml.Theta, result, err)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking real-line parameter...")
+	result, err = FitN(logClayton{NewCopula("clayton", 1.)}, M)
+	if err != nil || math.Abs(math.Exp(result.Params[0])-ml.Theta) > 1e-3 || !(result.StdErr[0] > 0.) {
+		t.Errorf("Bad fit on the real line, expected %f, got %v (%v)", math.Log(ml.Theta), result, err)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking non-finite optimum...")
+	result, err = FitN(&driftModel{p: 1.}, M)
+	if err == nil || !strings.HasPrefix(result.Message, "Error") {
+		t.Errorf("A non-finite optimum should be an error (%v)", result)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,239 @@
+// nonparametric.go
+
+package gopula
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// EmpiricalGenerator is a nonparametric archimedean generator estimated
+// from the empirical Kendall distribution (Genest, Nešlehová and Ziegel,
+// 2011). It is the Williamson d-transform of a discrete radial
+// distribution: psi(x) = sum_j p_j (1 - x/r_j)_+^(d-1). In the bivariate
+// case it is the generator of Genest and Rivest (1993). The parameter
+// theta is ignored.
+type EmpiricalGenerator struct {
+	// Radii are the (increasing) atoms of the radial distribution
+	Radii []float64
+	// Masses are the probabilities of the atoms
+	Masses []float64
+	// Dim is the dimension d of the generator (it is d-monotone)
+	Dim int
+	// Bandwidth is the bandwidth of the gaussian kernel smoothing
+	// the log-radii (used by the density only)
+	Bandwidth float64
+}
+
+// NewEmpiricalGenerator estimates the generator from the observations
+// (uniforms or pseudo-observations). The atoms r_k of the radial
+// distribution solve psi(r_k) = w_k where the w_k are the distinct
+// (rescaled) Kendall pseudo-observations (see KendallProcess) and their
+// masses are the ones of the empirical Kendall distribution.
+func NewEmpiricalGenerator(M *mat.Dense) (*EmpiricalGenerator, error) {
+	n, d := M.Dims()
+	if d < 2 || n < 3 {
+		return nil, errors.New("At least 3 observations with 2 columns are required")
+	}
+	kp := NewKendallProcess(M)
+
+	// distinct values in decreasing order (the last one is 0). They are
+	// rescaled to count/(n+1) so that w_k < P(W < w_k): the observations
+	// lower than the k-th one have a lower pseudo-observation.
+	scale := float64(n-1) / float64(n+1)
+	w := make([]float64, 0)
+	p := make([]float64, 0)
+	for i := n - 1; i >= 0; i-- {
+		if len(w) > 0 && scale*kp.W[i] == w[len(w)-1] {
+			p[len(p)-1] += 1. / float64(n)
+			continue
+		}
+		w = append(w, scale*kp.W[i])
+		p = append(p, 1./float64(n))
+	}
+	m := len(w)
+	if m < 2 || w[m-1] != 0. {
+		return nil, errors.New("The Kendall pseudo-observations are degenerate")
+	}
+
+	gen := &EmpiricalGenerator{Radii: make([]float64, m), Masses: p, Dim: d}
+	r := gen.Radii
+	r[m-1] = 1.
+	// psi(r_k) only depends on the atoms greater than r_k
+	// so the radii are computed backward
+	for k := m - 2; k >= 0; k-- {
+		fun := func(y float64, args interface{}) float64 {
+			s := 0.
+			for j := k + 1; j < m; j++ {
+				s += p[j] * math.Pow(1.-y/r[j], float64(d-1))
+			}
+			return s - w[k]
+		}
+		if fun(0., nil) <= 0. {
+			// safeguard against rounding errors
+			r[k] = 1e-3 * r[k+1]
+			continue
+		}
+		y, err := Bisection(fun, nil, 0., r[k+1], 1e-12*r[k+1])
+		if err != nil {
+			return nil, err
+		}
+		r[k] = y
+	}
+
+	// Silverman's rule on the log-radii
+	logMean := 0.
+	for j := range r {
+		logMean += p[j] * math.Log(r[j])
+	}
+	logVar := 0.
+	for j := range r {
+		logVar += p[j] * math.Pow(math.Log(r[j])-logMean, 2)
+	}
+	gen.Bandwidth = 1.06 * math.Sqrt(logVar) * math.Pow(float64(n), -0.2)
+	if !(gen.Bandwidth > 0.) {
+		gen.Bandwidth = 0.1
+	}
+	return gen, nil
+}
+
+// NewEmpiricalArchimedean returns the archimedean copula whose generator is
+// estimated from the observations (see NewEmpiricalGenerator)
+func NewEmpiricalArchimedean(M *mat.Dense) (*ArchimedeanCopula, error) {
+	gen, err := NewEmpiricalGenerator(M)
+	if err != nil {
+		return nil, err
+	}
+	return NewCustomCopula(gen, 1.), nil
+}
+
+// NewCustomCopula returns the archimedean copula built on a
+// user-defined generator
+func NewCustomCopula(copula ArchimedeanCopuler, theta float64) *ArchimedeanCopula {
+	return &ArchimedeanCopula{theta: theta, copula: copula}
+}
+
+// Family returns the name of the copula family
+func (c *EmpiricalGenerator) Family() string {
+	return "Empirical"
+}
+
+// ThetaBounds returns the range where the copula is well defined
+// (the parameter is ignored)
+func (c *EmpiricalGenerator) ThetaBounds() (float64, float64) {
+	return 0., 2.
+}
+
+// Psi is the generating function of the copula
+func (c *EmpiricalGenerator) Psi(t float64, theta float64) float64 {
+	return c.PsiD(0, t, theta)
+}
+
+// PsiInv is the inverse of the generating function of the copula.
+// The radii may span many orders of magnitude so it is computed by
+// bisection on the logarithm of the support (0, max(Radii)].
+func (c *EmpiricalGenerator) PsiInv(t float64, theta float64) float64 {
+	rMax := c.Radii[len(c.Radii)-1]
+	if t <= 0. {
+		return rMax
+	}
+	if t >= 1. {
+		return 0.
+	}
+	fun := func(s float64, args interface{}) float64 {
+		return c.Psi(math.Exp(s), theta) - t
+	}
+	lower := math.Log(c.Radii[0]) - 20.
+	if fun(lower, nil) <= 0. {
+		return 0.
+	}
+	s, err := Bisection(fun, nil, lower, math.Log(rMax), 1e-12)
+	if err != nil {
+		return math.NaN()
+	}
+	return math.Exp(s)
+}
+
+// PsiD is the d-th derivative of Psi. The Dim-th derivative does not exist
+// for a discrete radial distribution, it is computed from the kernel
+// estimate of the radial density:
+// psi^(d)(x) = (-1)^d (d-1)! f_R(x) / x^(d-1)
+func (c *EmpiricalGenerator) PsiD(dim int, t float64, theta float64) float64 {
+	if dim > c.Dim || t < 0. {
+		return math.NaN()
+	}
+	coeff := 1.
+	if dim%2 == 1 {
+		coeff = -1.
+	}
+	if dim == c.Dim {
+		density := 0.
+		for j, r := range c.Radii {
+			z := (math.Log(t) - math.Log(r)) / c.Bandwidth
+			density += c.Masses[j] * math.Exp(-0.5*z*z) / (math.Sqrt(2.*math.Pi) * c.Bandwidth * t)
+		}
+		return coeff * float64(factorial(c.Dim-1)) * density / math.Pow(t, float64(c.Dim-1))
+	}
+	// (d-1)! / (d-1-k)!
+	falling := 1.
+	for i := 0; i < dim; i++ {
+		falling *= float64(c.Dim - 1 - i)
+	}
+	s := 0.
+	for j, r := range c.Radii {
+		if t < r {
+			s += c.Masses[j] * math.Pow(1.-t/r, float64(c.Dim-1-dim)) / math.Pow(r, float64(dim))
+		}
+	}
+	return coeff * falling * s
+}
+
+// RadialPpf computes the quantile of the discrete radial distribution
+// (NaN when dim is not the dimension of the generator)
+func (c *EmpiricalGenerator) RadialPpf(p float64, dim int, theta float64) float64 {
+	if dim != c.Dim || p <= 0. || p >= 1. {
+		return math.NaN()
+	}
+	cum := 0.
+	for j, r := range c.Radii {
+		cum += c.Masses[j]
+		if cum >= p {
+			return r
+		}
+	}
+	return c.Radii[len(c.Radii)-1]
+}
+
+// t computes  PsiInv(u_1) + PsiInv(u_2) ... + PsiInv(u_d)
+func (c *EmpiricalGenerator) t(vector []float64, theta float64) float64 {
+	sum := 0.
+	for _, x := range vector {
+		sum += c.PsiInv(x, theta)
+	}
+	return sum
+}
+
+// Cdf computes the cumulative distribution function
+// of the copula
+func (c *EmpiricalGenerator) Cdf(vector []float64, theta float64) float64 {
+	return c.Psi(c.t(vector, theta), theta)
+}
+
+// Pdf computes the density of the generated copula
+func (c *EmpiricalGenerator) Pdf(vector []float64, theta float64) float64 {
+	if min(vector) <= 0. || max(vector) >= 1. {
+		return 0.
+	}
+	p := c.PsiD(len(vector), c.t(vector, theta), theta)
+	for _, u := range vector {
+		p /= c.PsiD(1, c.PsiInv(u, theta), theta)
+	}
+	return p
+}
+
+// LogPdf computes the log density of the generated copula
+func (c *EmpiricalGenerator) LogPdf(vector []float64, theta float64) float64 {
+	return math.Log(c.Pdf(vector, theta))
+}
@@ -0,0 +1,99 @@
+// nonparametric_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestInitNonParametric(t *testing.T) {
+	title("Nonparametric generator")
+}
+
+func TestEmpiricalGenerator(t *testing.T) {
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clayton := NewCopula("clayton", 2.1)
+
+	for _, d := range []int{2, 3} {
+		checkTitle("Checking empirical generator...")
+		S := M.Slice(0, 500, 0, d).(*mat.Dense)
+		gen, err := NewEmpiricalGenerator(S)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rMax := gen.Radii[len(gen.Radii)-1]
+		if math.Abs(gen.Psi(0., 1.)-1.) > 1e-12 || gen.Psi(rMax, 1.) != 0. {
+			t.Errorf("Bad generator bounds, got psi(0) = %f and psi(max) = %f", gen.Psi(0., 1.), gen.Psi(rMax, 1.))
+			testERROR()
+			continue
+		}
+		x := 0.3 * rMax
+		if y := gen.PsiInv(gen.Psi(x, 1.), 1.); math.Abs(y-x) > 1e-9 {
+			t.Errorf("Bad inverse generator, expected %f, got %f", x, y)
+			testERROR()
+			continue
+		}
+		testOK()
+
+		checkTitle("Checking empirical copula against clayton...")
+		cop := NewCustomCopula(gen, 1.)
+		ok := true
+		for _, u := range []float64{0.2, 0.5, 0.8} {
+			vector := make([]float64, d)
+			for j := range vector {
+				vector[j] = u
+			}
+			if c, expected := cop.Cdf(vector), clayton.Cdf(vector); math.Abs(c-expected) > 0.05 {
+				t.Errorf("Bad empirical cdf at %v, expected %f, got %f", vector, expected, c)
+				ok = false
+			}
+			if p := cop.Pdf(vector); !(p > 0.) || math.IsInf(p, 0) {
+				t.Errorf("Bad empirical density at %v (%f)", vector, p)
+				ok = false
+			}
+		}
+		if ok {
+			testOK()
+		} else {
+			testERROR()
+		}
+	}
+}
+
+func TestEmpiricalCopulaSample(t *testing.T) {
+	checkTitle("Checking empirical copula sampling...")
+	M, err := LoadCSV(claytonSample, ',', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	X := M.Slice(0, 500, 0, 2).(*mat.Dense)
+	cop, err := NewEmpiricalArchimedean(X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	S := cop.Sample(500, 2)
+	for i := 0; i < 500; i++ {
+		for _, u := range S.RawRowView(i) {
+			if !(u >= 0. && u <= 1.) {
+				t.Errorf("Bad sample %v", S.RawRowView(i))
+				testERROR()
+				return
+			}
+		}
+	}
+	// the sample must keep the dependence of the data
+	expected := stat.Kendall(rawCol(X, 0), rawCol(X, 1), nil)
+	if tau := stat.Kendall(rawCol(S, 0), rawCol(S, 1), nil); math.Abs(tau-expected) > 0.1 {
+		t.Errorf("Bad sample, expected tau = %f, got %f", expected, tau)
+		testERROR()
+		return
+	}
+	testOK()
+}
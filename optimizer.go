@@ -361,3 +361,56 @@ func Secant(fun ObjectiveFunction, args interface{}, x1, x2, xacc float64) (floa
 	return 0.0, fmt.Errorf("Maximum number of function evaluations reached")
 	//  Never get here.
 }
+
+// -------------------------------------------------------------------------- //
+// ------------------------------- DERIVATIVES ------------------------------ //
+// -------------------------------------------------------------------------- //
+
+// SecondDerivative estimates the second derivative of the function f at x
+// with the Ridders' method: central differences computed with decreasing
+// steps (starting from h) are extrapolated to a zero step. It returns the
+// estimate and an estimate of its error. The adaptation from the first
+// derivative directly comes from the book 'Numerical Recipes in C' (p. 188, 189)
+func SecondDerivative(f ObjectiveFunction, args interface{}, x, h float64) (float64, float64) {
+	const (
+		con  = 1.4
+		con2 = con * con
+		ntab = 10
+		safe = 2.
+	)
+	var a [ntab][ntab]float64
+	fx := f(x, args)
+	central := func(step float64) float64 {
+		return (f(x+step, args) - 2.*fx + f(x-step, args)) / (step * step)
+	}
+
+	hh := h
+	a[0][0] = central(hh)
+	ans := a[0][0]
+	err := math.Inf(1)
+	for i := 1; i < ntab; i++ {
+		// successive columns in the Neville tableau will go to
+		// smaller stepsizes and higher orders of extrapolation
+		hh /= con
+		a[0][i] = central(hh)
+		fac := con2
+		for j := 1; j <= i; j++ {
+			// compute extrapolations of various orders
+			a[j][i] = (a[j-1][i]*fac - a[j-1][i-1]) / (fac - 1.)
+			fac = con2 * fac
+			errt := math.Max(math.Abs(a[j][i]-a[j-1][i]), math.Abs(a[j][i]-a[j-1][i-1]))
+			// the error strategy is to compare each new extrapolation
+			// to one order lower, both at the present stepsize and
+			// the previous one
+			if errt <= err {
+				err = errt
+				ans = a[j][i]
+			}
+		}
+		// if higher order is worse by a significant factor, quit early
+		if math.Abs(a[i][i]-a[i-1][i-1]) >= safe*err {
+			break
+		}
+	}
+	return ans, err
+}
@@ -134,3 +134,20 @@ func TestOptimizerComparison(t *testing.T) {
 	fmt.Printf("Brent  %-12.6f %-12.6f %-12d\n", thetaBest, -llhood, nit)
 	fmt.Println("--------------------------------------")
 }
+
+func TestSecondDerivative(t *testing.T) {
+	checkTitle("Testing Second Derivative...")
+	k := 3.0
+	x := 1.7
+	sol := -k*(k-1)*math.Pow(x, k-2)*math.Exp(-x) +
+		2*k*math.Pow(x, k-1)*math.Exp(-x) -
+		math.Pow(x, k)*math.Exp(-x)
+
+	d2, _ := SecondDerivative(fun0, k, x, 0.5)
+	if math.Abs(d2-sol) > 1e-8 {
+		testERROR()
+		t.Errorf("Bad second derivative (expected %f, got %f)", sol, d2)
+	} else {
+		testOK()
+	}
+}
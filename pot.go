@@ -0,0 +1,124 @@
+// pot.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// excesses returns the x - u of the observations x greater than u
+func excesses(x []float64, u float64) []float64 {
+	y := make([]float64, 0)
+	for _, v := range x {
+		if v > u {
+			y = append(y, v-u)
+		}
+	}
+	return y
+}
+
+// SemiParametricMargin is a margin made of the empirical cdf of the
+// observations below a threshold u and of a generalized Pareto tail above
+// (peaks over threshold): F(x) = F_n(u) + (1 - F_n(u)) G(x - u) for x > u.
+// Unlike the empirical cdf, it extrapolates beyond the largest observation.
+type SemiParametricMargin struct {
+	// Threshold is the level u above which the tail is modelled
+	Threshold float64
+	// Tail is the distribution of the excesses over the threshold
+	Tail *GeneralizedPareto
+	ecdf *ECDF
+	// body is the value of the empirical cdf at the threshold
+	body float64
+}
+
+// NewSemiParametricMargin fits the generalized Pareto distribution of the
+// excesses of the observations over the threshold by maximum likelihood
+// (see FitGeneralizedPareto)
+func NewSemiParametricMargin(x []float64, threshold float64) (*SemiParametricMargin, error) {
+	tail, err := FitGeneralizedPareto(excesses(x, threshold))
+	if err != nil {
+		return nil, fmt.Errorf("Bad threshold %f: %s", threshold, err.Error())
+	}
+	ecdf := NewECDF(x)
+	return &SemiParametricMargin{
+		Threshold: threshold,
+		Tail:      tail,
+		ecdf:      ecdf,
+		body:      ecdf.CDF(threshold),
+	}, nil
+}
+
+// CDF computes the cumulative distribution function
+func (m *SemiParametricMargin) CDF(x float64) float64 {
+	if x <= m.Threshold {
+		return m.ecdf.CDF(x)
+	}
+	return m.body + (1.-m.body)*m.Tail.CDF(x-m.Threshold)
+}
+
+// Quantile computes the value x such that F(x) = p. In the body, it is the
+// smallest observation whose empirical cdf reaches p.
+func (m *SemiParametricMargin) Quantile(p float64) float64 {
+	if p > m.body {
+		return m.Threshold + m.Tail.Quantile((p-m.body)/(1.-m.body))
+	}
+	n := len(m.ecdf.sorted)
+	k := int(math.Ceil(p*float64(n+1))) - 1
+	return m.ecdf.sorted[int(math.Max(0., math.Min(float64(n-1), float64(k))))]
+}
+
+// ThresholdDiagnostics gathers the data of the plots helping to choose
+// the threshold of a peaks over threshold model. Above a suitable
+// threshold, the mean excess is linear in the threshold while the shape
+// and the modified scale of the fitted tail are constant.
+type ThresholdDiagnostics struct {
+	// Thresholds are the candidate thresholds
+	Thresholds []float64
+	// Exceedances are the numbers of observations above the thresholds
+	Exceedances []int
+	// MeanExcess are the means of the excesses over the thresholds
+	MeanExcess []float64
+	// Shape are the shapes of the fitted generalized Pareto distributions
+	Shape []float64
+	// ShapeStdErr are the asymptotic standard errors (1 + Shape) / sqrt(N_u)
+	// of the shapes
+	ShapeStdErr []float64
+	// ModifiedScale are the scales reparametrized as Scale - Shape u
+	// so that they do not depend on the threshold u
+	ModifiedScale []float64
+}
+
+// NewThresholdDiagnostics computes the mean excess and fits the tail over
+// every candidate threshold (sorted increasingly). The values are NaN when
+// the fit fails, typically when there are too few exceedances.
+func NewThresholdDiagnostics(x []float64, thresholds []float64) *ThresholdDiagnostics {
+	k := len(thresholds)
+	td := &ThresholdDiagnostics{
+		Thresholds:    createCopy(thresholds),
+		Exceedances:   make([]int, k),
+		MeanExcess:    make([]float64, k),
+		Shape:         make([]float64, k),
+		ShapeStdErr:   make([]float64, k),
+		ModifiedScale: make([]float64, k),
+	}
+	sort.Float64s(td.Thresholds)
+	for i, u := range td.Thresholds {
+		y := excesses(x, u)
+		td.Exceedances[i] = len(y)
+		td.MeanExcess[i] = math.NaN()
+		if len(y) > 0 {
+			td.MeanExcess[i] = mean(y)
+		}
+		tail, err := FitGeneralizedPareto(y)
+		if err != nil {
+			td.Shape[i], td.ShapeStdErr[i], td.ModifiedScale[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+		td.Shape[i] = tail.Shape
+		td.ShapeStdErr[i] = (1. + tail.Shape) / math.Sqrt(float64(len(y)))
+		td.ModifiedScale[i] = tail.Scale - tail.Shape*u
+	}
+	return td
+}
@@ -0,0 +1,134 @@
+// pot_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitPOT(t *testing.T) {
+	title("Peaks over threshold")
+}
+
+func TestSemiParametricMargin(t *testing.T) {
+	g := &GeneralizedPareto{Shape: 0.3, Scale: 1.}
+	x := gpdSample(g, 5000, 2)
+	m, err := NewSemiParametricMargin(x, 2.)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTitle("Checking tail fit...")
+	// the excesses over u follow a GPD with the same shape and the scale 1 + 0.3 u
+	if math.Abs(m.Tail.Shape-0.3) > 0.1 || math.Abs(m.Tail.Scale-1.6) > 0.2 {
+		t.Errorf("Bad tail (shape = %f, scale = %f)", m.Tail.Shape, m.Tail.Scale)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking continuity at the threshold...")
+	if math.Abs(m.CDF(2.+1e-9)-m.CDF(2.)) > 1e-6 || math.Abs(m.CDF(2.)-g.CDF(2.)) > 0.02 {
+		t.Errorf("The cdf should be continuous at the threshold (%f, %f)", m.CDF(2.), m.CDF(2.+1e-9))
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking extrapolation...")
+	xM := max(x)
+	p := m.CDF(2. * xM)
+	if !(p > m.CDF(xM) && p < 1.) || math.Abs(m.Quantile(p)-2.*xM) > 1e-6*xM {
+		t.Errorf("The tail should extrapolate beyond the largest observation (F = %f)", p)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking body quantile...")
+	q := m.Quantile(0.5)
+	if math.Abs(m.CDF(q)-0.5) > 1e-3 || math.Abs(q-g.Quantile(0.5)) > 0.05 {
+		t.Errorf("Bad median (%f)", q)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking threshold...")
+	if _, err := NewSemiParametricMargin(x, 2.*xM); err == nil {
+		t.Errorf("The threshold should be too high")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestThresholdDiagnostics(t *testing.T) {
+	g := &GeneralizedPareto{Shape: 0.3, Scale: 1.}
+	x := gpdSample(g, 5000, 3)
+	td := NewThresholdDiagnostics(x, []float64{2., 0., 1., 1e6})
+
+	checkTitle("Checking mean excess...")
+	ok := td.Thresholds[0] == 0. && td.Exceedances[0] == 5000 && td.Exceedances[3] == 0 &&
+		math.IsNaN(td.MeanExcess[3]) && math.IsNaN(td.Shape[3])
+	for i := 0; i < 3; i++ {
+		// e(u) = (scale + shape u) / (1 - shape)
+		u := td.Thresholds[i]
+		if math.Abs(td.MeanExcess[i]/((1.+0.3*u)/0.7)-1.) > 0.15 {
+			ok = false
+		}
+	}
+	if !ok {
+		t.Errorf("Bad mean excess %v", td.MeanExcess)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking parameter stability...")
+	ok = true
+	for i := 0; i < 3; i++ {
+		if math.Abs(td.Shape[i]-0.3) > 3.*td.ShapeStdErr[i] || math.Abs(td.ModifiedScale[i]-1.) > 0.2 {
+			ok = false
+		}
+	}
+	if !ok {
+		t.Errorf("The parameters should be stable (shape %v, modified scale %v)", td.Shape, td.ModifiedScale)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestSemiParametricJointDistribution(t *testing.T) {
+	g := &GeneralizedPareto{Shape: 0.3, Scale: 1.}
+	X := NewCopula("clayton", 2.1).sampleWith(3000, 2, rand.New(rand.NewSource(4)))
+	X.Apply(func(i, j int, v float64) float64 { return g.Quantile(v) }, X)
+
+	margins := make([]Margin, 2)
+	for j := range margins {
+		m, err := NewSemiParametricMargin(rawCol(X, j), g.Quantile(0.9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		margins[j] = m
+	}
+
+	checkTitle("Checking joint distribution with GPD tails...")
+	jd := NewJointDistribution(NewCopula("clayton", 1.), margins)
+	result, err := jd.Fit(X, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := jd.Cdf([]float64{100., 100.})
+	if math.Abs(result.Theta-2.1) > 0.2 || !(p < 1.) || jd.Cdf(mat.Row(nil, 0, X)) <= 0. {
+		t.Errorf("Bad joint distribution (theta = %f, F(100, 100) = %f)", result.Theta, p)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -0,0 +1,310 @@
+// robust.go
+
+package gopula
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// QMCPoints is the number of quasi-Monte Carlo points used to
+// integrate over the unit cube
+var QMCPoints = 4096
+
+// primes are the bases of the Halton sequence
+var primes = []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53}
+
+// ErrRobustCensoring is returned when a robust fit is run
+// on censored observations
+var ErrRobustCensoring = errors.New("The robust fits do not handle censoring")
+
+// haltonPoints returns the first n points of the Halton sequence
+// in dimension d (the point 0 is skipped)
+func haltonPoints(n int, d int) *mat.Dense {
+	H := mat.NewDense(n, d, nil)
+	for j := 0; j < d; j++ {
+		base := primes[j%len(primes)]
+		for i := 0; i < n; i++ {
+			f, r := 1., 0.
+			for k := i + 1; k > 0; k /= base {
+				f /= float64(base)
+				r += f * float64(k%base)
+			}
+			H.Set(i, j, r)
+		}
+	}
+	return H
+}
+
+// dpdArgs gathers the arguments of the density power divergence
+type dpdArgs struct {
+	M     *mat.Dense
+	nodes *mat.Dense
+	alpha float64
+}
+
+// densityPower computes c(u)^alpha (NaN when the density is not defined)
+func (arch *ArchimedeanCopula) densityPower(vector []float64, theta float64, alpha float64) float64 {
+	return math.Exp(alpha * arch.copula.LogPdf(vector, theta))
+}
+
+// dpdIntegral computes the integral of c^(1+alpha) over the cube
+// with the quasi-Monte Carlo nodes
+func (arch *ArchimedeanCopula) dpdIntegral(theta float64, args *dpdArgs) float64 {
+	nodes, _ := args.nodes.Dims()
+	s := 0.
+	for k := 0; k < nodes; k++ {
+		if c := arch.densityPower(args.nodes.RawRowView(k), theta, 1.+args.alpha); !math.IsNaN(c) {
+			s += c
+		}
+	}
+	return s / float64(nodes)
+}
+
+// dpdToMinimize computes the density power divergence of Basu et al.
+// (1998) up to a constant:
+// int c^(1+alpha) - (1 + 1/alpha) 1/n sum_i c(U_i)^alpha
+func (arch *ArchimedeanCopula) dpdToMinimize(theta float64, args interface{}) float64 {
+	a := args.(*dpdArgs)
+	n, _ := a.M.Dims()
+	s := 0.
+	for i := 0; i < n; i++ {
+		if c := arch.densityPower(a.M.RawRowView(i), theta, a.alpha); !math.IsNaN(c) {
+			s += c
+		}
+	}
+	return arch.dpdIntegral(theta, a) - (1.+1./a.alpha)*s/float64(n)
+}
+
+// fitDensityPowerDivergence estimates theta by minimizing the density
+// power divergence between the copula and the observations. The power
+// opts.Alpha balances robustness (large alpha) and efficiency (the
+// estimator tends to the maximum likelihood one when alpha goes to 0).
+// The observations are weighted by c(U_i)^alpha so that the ones lying
+// where the fitted density is low barely contribute.
+func (arch *ArchimedeanCopula) fitDensityPowerDivergence(M *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	if opts.Censoring != nil {
+		return nil, ErrRobustCensoring
+	}
+	n, d := M.Dims()
+	alpha := opts.Alpha
+	if !(alpha > 0.) {
+		alpha = 0.25
+	}
+	args := &dpdArgs{M: M, nodes: haltonPoints(QMCPoints, d), alpha: alpha}
+
+	thetaBest, _, feval, msg, err := arch.searchTheta(arch.dpdToMinimize, args, opts)
+	if err != nil {
+		msg += "Error: " + err.Error()
+	} else {
+		msg += "Success"
+	}
+	msg += fmt.Sprintf(" (density power divergence with alpha = %.3f)", alpha)
+	arch.theta = thetaBest
+
+	result := &FitResult{
+		Theta:          thetaBest,
+		LogLikelihood:  arch.LogLikelihood(M),
+		StdErr:         math.NaN(),
+		SandwichStdErr: math.NaN(),
+		Level:          opts.Level,
+		UpperBound:     math.NaN(),
+		LowerBound:     math.NaN(),
+		Evals:          feval,
+		Message:        msg,
+		Weights:        make([]float64, n)}
+
+	for i := 0; i < n; i++ {
+		result.Weights[i] = arch.densityPower(M.RawRowView(i), thetaBest, alpha)
+	}
+	result.Rejected = normalizeWeights(result.Weights)
+
+	// sandwich standard error of the M-estimator: the derivatives of the
+	// contributions (the integral term is shared) over the curvature
+	// of the divergence
+	J := arch.observedInformation(arch.dpdToMinimize, args)
+	if !(J > 0.) {
+		return result, err
+	}
+	h := arch.thetaStep()
+	psi := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		row := M.RawRowView(i)
+		s := (arch.densityPower(row, thetaBest+h, alpha) - arch.densityPower(row, thetaBest-h, alpha)) / (2. * h)
+		if !math.IsNaN(s) && !math.IsInf(s, 0) {
+			psi = append(psi, (1.+1./alpha)*s)
+		}
+	}
+	m := mean(psi)
+	K := 0.
+	for _, s := range psi {
+		K += (s - m) * (s - m)
+	}
+	K /= float64(len(psi))
+	result.StdErr = math.Sqrt(K/float64(len(psi))) / J
+	result.SandwichStdErr = result.StdErr
+	return result, err
+}
+
+// normalizeWeights divides the weights by their mean (NaN weights are
+// set to 0) and returns the proportion of the weights lower than 0.1
+func normalizeWeights(w []float64) float64 {
+	s := 0.
+	for i, x := range w {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			w[i] = 0.
+		}
+		s += w[i]
+	}
+	if s == 0. {
+		return 1.
+	}
+	low := 0
+	for i := range w {
+		w[i] *= float64(len(w)) / s
+		if w[i] < 0.1 {
+			low++
+		}
+	}
+	return float64(low) / float64(len(w))
+}
+
+// trimmedArgs gathers the arguments of the trimmed likelihood
+type trimmedArgs struct {
+	// K are the kept observations
+	K     *mat.Dense
+	nodes *mat.Dense
+	// outside are the indices of the nodes lying in the trimmed region
+	outside []int
+}
+
+// trimmedLikelihoodToMinimize computes the opposite of the log-likelihood
+// of the kept observations conditionally on the kept region A, i.e. the
+// densities are rescaled by P(U in A) = 1 - int_{A^c} c (computed with the
+// quasi-Monte Carlo nodes lying in the trimmed region A^c)
+func (arch *ArchimedeanCopula) trimmedLikelihoodToMinimize(theta float64, args interface{}) float64 {
+	a := args.(*trimmedArgs)
+	h, _ := a.K.Dims()
+	nodes, _ := a.nodes.Dims()
+	s := 0.
+	for _, k := range a.outside {
+		if c := arch.densityPower(a.nodes.RawRowView(k), theta, 1.); !math.IsNaN(c) && !math.IsInf(c, 0) {
+			s += c
+		}
+	}
+	mass := 1. - s/float64(nodes)
+	if !(mass > 0.) {
+		return math.Inf(1)
+	}
+	return arch.logLikelihoodToMinimize(theta, a.K) + float64(h)*math.Log(mass)
+}
+
+// fitTrimmed estimates theta by maximizing the likelihood of the
+// (1 - opts.Trim) n observations having the largest log-density
+// (trimmed likelihood of Neykov et al., 2007). The kept subset is found
+// with concentration steps starting from the maximum likelihood fit,
+// until it does not change anymore. Since the trimmed observations are
+// the ones where the density is low, the plain likelihood of the kept
+// subset overestimates the dependence: the kept observations are rather
+// modelled by the copula conditioned on the region where the log-density
+// at the current estimate exceeds the one of the last kept observation
+// (see trimmedLikelihoodToMinimize). The estimator is then consistent and
+// its breakdown point is the trimmed proportion.
+func (arch *ArchimedeanCopula) fitTrimmed(M *mat.Dense, opts *FitOptions) (*FitResult, error) {
+	if opts.Censoring != nil {
+		return nil, ErrRobustCensoring
+	}
+	n, d := M.Dims()
+	trim := opts.Trim
+	if !(trim > 0.) {
+		trim = 0.1
+	}
+	if trim >= 0.5 {
+		return nil, fmt.Errorf("The trimmed proportion must be lower than 0.5 (got %f)", trim)
+	}
+	h := n - int(math.Floor(trim*float64(n)))
+	if h < 2 {
+		return nil, errors.New("Too few observations are kept")
+	}
+
+	thetaBest, _, feval, msg, err := arch.searchTheta(arch.logLikelihoodToMinimize, M, opts)
+	kept := make([]int, n)
+	args := &trimmedArgs{nodes: haltonPoints(QMCPoints, d)}
+	nodes, _ := args.nodes.Dims()
+	for step := 0; step < 50; step++ {
+		// the observations are sorted by decreasing log-density
+		// (NaN ones at the end)
+		l := make([]float64, n)
+		order := make([]int, n)
+		for i := 0; i < n; i++ {
+			l[i] = arch.copula.LogPdf(M.RawRowView(i), thetaBest)
+			if math.IsNaN(l[i]) {
+				l[i] = math.Inf(-1)
+			}
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return l[order[a]] > l[order[b]] })
+		subset := append([]int{}, order[:h]...)
+		sort.Ints(subset)
+		if step > 0 && equalInts(subset, kept[:h]) {
+			break
+		}
+		copy(kept, subset)
+		args.K = selectRows(M, subset)
+		threshold := l[order[h-1]]
+		args.outside = args.outside[:0]
+		for k := 0; k < nodes; k++ {
+			if !(arch.copula.LogPdf(args.nodes.RawRowView(k), thetaBest) >= threshold) {
+				args.outside = append(args.outside, k)
+			}
+		}
+
+		var evals int
+		thetaBest, _, evals, msg, err = arch.minimizeTheta(arch.trimmedLikelihoodToMinimize, args)
+		feval += evals
+	}
+	if err != nil {
+		msg += "Error: " + err.Error()
+	} else {
+		msg += "Success"
+	}
+	msg += fmt.Sprintf(" (trimmed likelihood keeping %d/%d observations)", h, n)
+	arch.theta = thetaBest
+
+	result := &FitResult{
+		Theta:          thetaBest,
+		LogLikelihood:  arch.LogLikelihood(M),
+		StdErr:         math.NaN(),
+		SandwichStdErr: math.NaN(),
+		Level:          opts.Level,
+		UpperBound:     math.NaN(),
+		LowerBound:     math.NaN(),
+		Evals:          feval,
+		Message:        msg,
+		Weights:        make([]float64, n),
+		Rejected:       float64(n-h) / float64(n)}
+	for _, i := range kept[:h] {
+		result.Weights[i] = 1.
+	}
+	if info := arch.observedInformation(arch.trimmedLikelihoodToMinimize, args); info > 0. {
+		result.StdErr = 1. / math.Sqrt(info)
+	}
+	return result, err
+}
+
+// equalInts checks whether two slices of integers are equal
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
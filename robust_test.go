@@ -0,0 +1,136 @@
+// robust_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitRobust(t *testing.T) {
+	title("Robust estimation")
+}
+
+// cornerContamination draws a clayton sample (theta = 2.1) where the
+// first rows are moved to the corners (0, 1) and (1, 0)
+func cornerContamination(n int, outliers int) *mat.Dense {
+	X := NewCopula("clayton", 2.1).sampleWith(n, 2, rand.New(rand.NewSource(3)))
+	for i := 0; i < outliers; i++ {
+		eps := 1e-3 * float64(i+1)
+		if i%2 == 0 {
+			X.SetRow(i, []float64{eps, 1. - eps})
+		} else {
+			X.SetRow(i, []float64{1. - eps, eps})
+		}
+	}
+	return X
+}
+
+func TestHaltonPoints(t *testing.T) {
+	checkTitle("Checking Halton points...")
+	H := haltonPoints(1024, 2)
+	ok := math.Abs(H.At(0, 0)-0.5) < 1e-15 && math.Abs(H.At(1, 1)-2./3.) < 1e-15
+	for j := 0; j < 2; j++ {
+		if math.Abs(mean(rawCol(H, j))-0.5) > 1e-2 {
+			ok = false
+		}
+	}
+	if !ok {
+		t.Errorf("Bad Halton sequence")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestFitDensityPowerDivergence(t *testing.T) {
+	X := cornerContamination(500, 25)
+	AC := NewCopula("clayton", 1.)
+	ml := AC.Fit(X)
+
+	checkTitle("Checking minimum density power divergence...")
+	opts := DefaultFitOptions()
+	opts.Method = DensityPowerDivergence
+	opts.Alpha = 0.5
+	result, err := AC.FitWithOptions(X, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Theta-2.1) > math.Abs(ml.Theta-2.1) || math.Abs(result.Theta-2.1) > 0.4 {
+		t.Errorf("The robust estimate should be closer to 2.1 (%f, ML: %f)", result.Theta, ml.Theta)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking density power divergence weights...")
+	if result.Weights[0] > 0.1 || result.Rejected < 0.04 || !(result.StdErr > 0.) {
+		t.Errorf("The corner observations should be downweighted (w = %f, rejected = %f)",
+			result.Weights[0], result.Rejected)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestFitTrimmed(t *testing.T) {
+	X := cornerContamination(500, 25)
+	AC := NewCopula("clayton", 1.)
+	ml := AC.Fit(X)
+
+	checkTitle("Checking trimmed likelihood...")
+	opts := DefaultFitOptions()
+	opts.Method = TrimmedLikelihood
+	opts.Trim = 0.1
+	result, err := AC.FitWithOptions(X, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Theta-2.1) > math.Abs(ml.Theta-2.1) || math.Abs(result.Theta-2.1) > 0.5 {
+		t.Errorf("The robust estimate should be closer to 2.1 (%f, ML: %f)", result.Theta, ml.Theta)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking trimmed observations...")
+	trimmed := 0
+	for i := 0; i < 25; i++ {
+		if result.Weights[i] == 0. {
+			trimmed++
+		}
+	}
+	if trimmed != 25 || math.Abs(result.Rejected-0.1) > 1e-10 {
+		t.Errorf("All the outliers should be trimmed (%d/25)", trimmed)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking trimmed likelihood on clean data...")
+	Y := NewCopula("clayton", 2.1).sampleWith(500, 2, rand.New(rand.NewSource(23)))
+	ml = AC.Fit(Y)
+	result, err = AC.FitWithOptions(Y, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// without outliers, the trimmed estimate should agree with the ML one
+	if math.Abs(result.Theta-ml.Theta) > 2.*result.StdErr || math.Abs(result.Theta-ml.Theta) > 0.2 {
+		t.Errorf("The trimmed estimate should stay near the ML one (%f, ML: %f)", result.Theta, ml.Theta)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking trimmed proportion...")
+	opts.Trim = 0.6
+	if _, err := AC.FitWithOptions(X, opts); err == nil {
+		t.Errorf("The trimmed proportion should be lower than 0.5")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
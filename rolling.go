@@ -0,0 +1,156 @@
+// rolling.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// WindowFit is the fit of the copula on the rows [Start, End)
+// of a time series
+type WindowFit struct {
+	Start  int
+	End    int
+	Result *FitResult
+	// Err is the error returned by the fit (if any)
+	Err error
+}
+
+// fitWindows fits a copula on every window of the time series. Every fit
+// starts from the estimate of the previous window (warm start), the
+// parameter of the copula being left unchanged.
+func (arch *ArchimedeanCopula) fitWindows(M *mat.Dense, windows [][2]int, opts *FitOptions) []*WindowFit {
+	if opts == nil {
+		opts = DefaultFitOptions()
+	}
+	warm := *opts
+	warm.WarmStart = true
+	cop := &ArchimedeanCopula{theta: arch.theta, copula: arch.copula}
+	_, d := M.Dims()
+	fits := make([]*WindowFit, len(windows))
+	for k, w := range windows {
+		// the first fit explores the whole domain
+		o := &warm
+		if k == 0 {
+			o = opts
+		}
+		result, err := cop.FitWithOptions(mat.DenseCopyOf(M.Slice(w[0], w[1], 0, d)), o)
+		fits[k] = &WindowFit{Start: w[0], End: w[1], Result: result, Err: err}
+	}
+	return fits
+}
+
+// RollingFit fits the copula on the sliding windows [k step, k step + window)
+// of the time-ordered observations (see FitWithOptions)
+func (arch *ArchimedeanCopula) RollingFit(M *mat.Dense, window int, step int, opts *FitOptions) ([]*WindowFit, error) {
+	n, _ := M.Dims()
+	if window < 2 || window > n || step < 1 {
+		return nil, fmt.Errorf("Bad window (%d) or step (%d) for %d observations", window, step, n)
+	}
+	windows := make([][2]int, 0)
+	for start := 0; start+window <= n; start += step {
+		windows = append(windows, [2]int{start, start + window})
+	}
+	return arch.fitWindows(M, windows, opts), nil
+}
+
+// ExpandingFit fits the copula on the growing windows [0, initial + k step)
+// of the time-ordered observations (see FitWithOptions)
+func (arch *ArchimedeanCopula) ExpandingFit(M *mat.Dense, initial int, step int, opts *FitOptions) ([]*WindowFit, error) {
+	n, _ := M.Dims()
+	if initial < 2 || initial > n || step < 1 {
+		return nil, fmt.Errorf("Bad initial window (%d) or step (%d) for %d observations", initial, step, n)
+	}
+	windows := make([][2]int, 0)
+	for end := initial; end <= n; end += step {
+		windows = append(windows, [2]int{0, end})
+	}
+	return arch.fitWindows(M, windows, opts), nil
+}
+
+// ChangePointResult details the output of a change-point test
+type ChangePointResult struct {
+	// Statistic is the maximum of the CUSUM process
+	Statistic float64
+	// PValue is the p-value of the test (no change in the dependence)
+	PValue float64
+	// Location is the number of observations before the most likely break
+	Location int
+	// CUSUM is the process k/sqrt(n) |tau_(1:k) - tau_(1:n)| (k = 1, ..., n)
+	CUSUM []float64
+}
+
+// tauCUSUM computes the CUSUM process of the Kendall's tau of the rows of
+// M taken in the given order (averaged over all the pairs of columns).
+// The concordances of every new row with the previous ones are
+// accumulated so that the process costs O(n² d²).
+func tauCUSUM(M *mat.Dense, order []int) []float64 {
+	n, d := M.Dims()
+	pairs := float64(d * (d - 1) / 2)
+	tau := make([]float64, n)
+	concordance := 0.
+	for k := 1; k < n; k++ {
+		x := M.RawRowView(order[k])
+		for j := 0; j < k; j++ {
+			y := M.RawRowView(order[j])
+			for a := 0; a < d; a++ {
+				for b := a + 1; b < d; b++ {
+					s := (x[a] - y[a]) * (x[b] - y[b])
+					if s > 0. {
+						concordance++
+					} else if s < 0. {
+						concordance--
+					}
+				}
+			}
+		}
+		tau[k] = 2. * concordance / (pairs * float64(k*(k+1)))
+	}
+	cusum := make([]float64, n)
+	for k := 1; k < n; k++ {
+		cusum[k] = float64(k+1) / math.Sqrt(float64(n)) * math.Abs(tau[k]-tau[n-1])
+	}
+	return cusum
+}
+
+// TauChangePoint tests whether the dependence of the time-ordered
+// observations changes over time with the CUSUM statistic of the
+// Kendall's tau of the first observations (Dehling et al., 2017):
+// max_k k/sqrt(n) |tau_(1:k) - tau_(1:n)|. The break is located at its
+// maximum. The p-value is computed by randomly permuting the
+// observations (opts.Replicates permutations), which assumes that they
+// are serially independent.
+func TauChangePoint(M *mat.Dense, opts *BootstrapOptions) (*ChangePointResult, error) {
+	if opts == nil {
+		opts = DefaultBootstrapOptions()
+	}
+	n, d := M.Dims()
+	if n < 4 || d < 2 {
+		return nil, fmt.Errorf("At least 4 observations with 2 columns are required (got %dx%d)", n, d)
+	}
+	identity := make([]int, n)
+	for i := range identity {
+		identity[i] = i
+	}
+	cusum := tauCUSUM(M, identity)
+	location := 0
+	for k, c := range cusum {
+		if c > cusum[location] {
+			location = k
+		}
+	}
+	statistic := cusum[location]
+	test := rankTestPValue(statistic, opts, func(rng *rand.Rand) float64 {
+		return max(tauCUSUM(M, rng.Perm(n)))
+	})
+	return &ChangePointResult{
+		Statistic: statistic,
+		PValue:    test.PValue,
+		Location:  location + 1,
+		CUSUM:     cusum,
+	}, nil
+}
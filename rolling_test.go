@@ -0,0 +1,99 @@
+// rolling_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInitRolling(t *testing.T) {
+	title("Rolling fits")
+}
+
+func TestRollingFit(t *testing.T) {
+	M := NewCopula("clayton", 2.1).sampleWith(1000, 2, rand.New(rand.NewSource(12)))
+	AC := NewCopula("clayton", 1.)
+	opts := DefaultFitOptions()
+	opts.Profile = false
+
+	checkTitle("Checking rolling fit...")
+	fits, err := AC.RollingFit(M, 400, 200, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := len(fits) == 4 && fits[3].Start == 600 && fits[3].End == 1000 &&
+		strings.HasPrefix(fits[1].Result.Message, "Warm start")
+	for _, f := range fits {
+		cold := NewCopula("clayton", 1.).Fit(mat.DenseCopyOf(M.Slice(f.Start, f.End, 0, 2)))
+		if f.Err != nil || math.Abs(f.Result.Theta-cold.Theta) > 1e-4 || math.Abs(f.Result.Theta-2.1) > 0.4 {
+			ok = false
+		}
+	}
+	if !ok || AC.Theta() != 1. {
+		t.Errorf("The warm started fits should match the cold ones")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking expanding fit...")
+	fits, err = AC.ExpandingFit(M, 500, 250, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fits) != 3 || fits[2].Start != 0 || fits[2].End != 1000 ||
+		math.Abs(fits[2].Result.Theta-AC.Fit(M).Theta) > 1e-4 {
+		t.Errorf("Bad expanding windows")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking rolling fit arguments...")
+	if _, err := AC.RollingFit(M, 2000, 10, opts); err == nil {
+		t.Errorf("The window should be shorter than the series")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestTauChangePoint(t *testing.T) {
+	opts := DefaultBootstrapOptions()
+	opts.Replicates = 100
+
+	checkTitle("Checking change-point test (break)...")
+	rng := rand.New(rand.NewSource(11))
+	M := mat.NewDense(400, 2, nil)
+	M.Slice(0, 250, 0, 2).(*mat.Dense).Copy(NewCopula("gumbel", 1.2).sampleWith(250, 2, rng))
+	M.Slice(250, 400, 0, 2).(*mat.Dense).Copy(NewCopula("gumbel", 4.).sampleWith(150, 2, rng))
+	result, err := TauChangePoint(M, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue > 0.05 || result.Location < 220 || result.Location > 280 {
+		t.Errorf("The break should be found after 250 observations (p = %f, location = %d)",
+			result.PValue, result.Location)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking change-point test (no break)...")
+	S := NewCopula("clayton", 2.1).sampleWith(400, 3, rand.New(rand.NewSource(6)))
+	result, err = TauChangePoint(S, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PValue < 0.05 || len(result.CUSUM) != 400 {
+		t.Errorf("No break should be found (p = %f)", result.PValue)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
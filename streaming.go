@@ -0,0 +1,172 @@
+// streaming.go
+
+package gopula
+
+import (
+	"fmt"
+	"math"
+)
+
+// streamingWarmUp is the number of first observations for which theta
+// is given by the inversion of the online Kendall's tau
+const streamingWarmUp = 20
+
+// StreamingEstimator estimates theta from observations received one at
+// a time, without storing the history. After a short warm-up where theta
+// is obtained by inverting the Kendall's tau, it is updated by recursive
+// maximum likelihood: theta_t = theta_(t-1) + s_t / I_t where s_t is the
+// score of the new observation and I_t = lambda I_(t-1) + s_t² is the
+// discounted information (lambda being the forgetting factor).
+// The Kendall's tau of the last observations is maintained alongside.
+type StreamingEstimator struct {
+	// Forgetting is the exponential forgetting factor lambda in (0, 1].
+	// The estimator roughly remembers the last 1/(1-lambda) observations.
+	Forgetting float64
+	// Window is the number of last observations used by the online
+	// Kendall's tau
+	Window int
+
+	copula ArchimedeanCopuler
+	theta  float64
+	info   float64
+	count  int
+	// ring buffer of the last observations
+	buffer      [][]float64
+	next        int
+	concordance float64
+}
+
+// NewStreamingEstimator returns a streaming estimator of the family of
+// the given copula, starting from its parameter
+func NewStreamingEstimator(copula *ArchimedeanCopula, forgetting float64, window int) (*StreamingEstimator, error) {
+	if !(forgetting > 0. && forgetting <= 1.) {
+		return nil, fmt.Errorf("The forgetting factor must lie in (0, 1] (got %f)", forgetting)
+	}
+	if window < 2 {
+		return nil, fmt.Errorf("The window must contain at least 2 observations (got %d)", window)
+	}
+	return &StreamingEstimator{
+		Forgetting: forgetting,
+		Window:     window,
+		copula:     copula.copula,
+		theta:      copula.theta,
+		buffer:     make([][]float64, 0, window),
+	}, nil
+}
+
+// signedConcordance computes the sum over the pairs of coordinates of
+// the sign of (x_a - y_a)(x_b - y_b)
+func signedConcordance(x []float64, y []float64) float64 {
+	s := 0.
+	for a := range x {
+		for b := a + 1; b < len(x); b++ {
+			p := (x[a] - y[a]) * (x[b] - y[b])
+			if p > 0. {
+				s++
+			} else if p < 0. {
+				s--
+			}
+		}
+	}
+	return s
+}
+
+// push adds an observation to the window of the online Kendall's tau
+// (the oldest one is dropped when the window is full)
+func (se *StreamingEstimator) push(u []float64) {
+	x := append([]float64{}, u...)
+	if len(se.buffer) == se.Window {
+		old := se.buffer[se.next]
+		for k, y := range se.buffer {
+			if k != se.next {
+				se.concordance -= signedConcordance(old, y)
+			}
+		}
+		se.buffer[se.next] = x
+		for k, y := range se.buffer {
+			if k != se.next {
+				se.concordance += signedConcordance(x, y)
+			}
+		}
+		se.next = (se.next + 1) % se.Window
+		return
+	}
+	for _, y := range se.buffer {
+		se.concordance += signedConcordance(x, y)
+	}
+	se.buffer = append(se.buffer, x)
+}
+
+// Tau returns the Kendall's tau of the observations of the window
+// (averaged over all the pairs of coordinates)
+func (se *StreamingEstimator) Tau() float64 {
+	m := len(se.buffer)
+	if m < 2 {
+		return math.NaN()
+	}
+	d := len(se.buffer[0])
+	pairs := float64(d * (d - 1) / 2)
+	return 2. * se.concordance / (pairs * float64(m*(m-1)))
+}
+
+// tauInversion returns the parameter of the family whose Kendall's tau is
+// tau (the current theta when tau is out of the range of the family)
+func (se *StreamingEstimator) tauInversion(tau float64) float64 {
+	a, b := se.copula.ThetaBounds()
+	cop := &ArchimedeanCopula{copula: se.copula}
+	fun := func(theta float64, args interface{}) float64 {
+		cop.theta = theta
+		return cop.KendallTau() - tau
+	}
+	if fun(a, nil)*fun(b, nil) > 0. {
+		return se.theta
+	}
+	theta, err := BrentRootFinder(fun, nil, a, b, 1e-8)
+	if err != nil {
+		return se.theta
+	}
+	return theta
+}
+
+// Update processes a new observation
+func (se *StreamingEstimator) Update(u []float64) error {
+	if len(se.buffer) > 0 && len(u) != len(se.buffer[0]) {
+		return fmt.Errorf("The observation has %d coordinates instead of %d", len(u), len(se.buffer[0]))
+	}
+	se.push(u)
+	se.count++
+
+	a, b := se.copula.ThetaBounds()
+	h := 1e-4 * math.Max(1., math.Abs(se.theta))
+	h = math.Min(h, 0.5*math.Min(se.theta-a, b-se.theta))
+	s := (se.copula.LogPdf(u, se.theta+h) - se.copula.LogPdf(u, se.theta-h)) / (2. * h)
+	if math.IsNaN(s) || math.IsInf(s, 0) {
+		return nil
+	}
+	se.info = se.Forgetting*se.info + s*s
+	if se.count <= streamingWarmUp {
+		if se.count >= 2 {
+			se.theta = se.tauInversion(se.Tau())
+		}
+		return nil
+	}
+	// the parameter is kept slightly inside the domain
+	margin := 1e-6 * (b - a)
+	se.theta = math.Max(a+margin, math.Min(b-margin, se.theta+s/se.info))
+	return nil
+}
+
+// Count returns the number of processed observations
+func (se *StreamingEstimator) Count() int {
+	return se.count
+}
+
+// Theta returns the current estimate
+func (se *StreamingEstimator) Theta() float64 {
+	return se.theta
+}
+
+// Snapshot returns a copula with the current estimate
+func (se *StreamingEstimator) Snapshot() *ArchimedeanCopula {
+	return &ArchimedeanCopula{theta: se.theta, copula: se.copula}
+}
@@ -0,0 +1,100 @@
+// streaming_test.go
+
+package gopula
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestInitStreaming(t *testing.T) {
+	title("Streaming estimation")
+}
+
+func TestStreamingEstimator(t *testing.T) {
+	M := NewCopula("clayton", 2.1).sampleWith(3000, 2, rand.New(rand.NewSource(13)))
+	se, err := NewStreamingEstimator(NewCopula("clayton", 1.), 0.999, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3000; i++ {
+		if err := se.Update(M.RawRowView(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkTitle("Checking streaming estimate...")
+	if se.Count() != 3000 || math.Abs(se.Theta()-2.1) > 0.3 {
+		t.Errorf("Bad estimate (%f instead of 2.1)", se.Theta())
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking online Kendall's tau...")
+	tau := 0.
+	for i := 2700; i < 3000; i++ {
+		for j := 2700; j < i; j++ {
+			tau += signedConcordance(M.RawRowView(i), M.RawRowView(j))
+		}
+	}
+	tau *= 2. / (300. * 299.)
+	if math.Abs(se.Tau()-tau) > 1e-12 {
+		t.Errorf("The tau of the window should be %f (got %f)", tau, se.Tau())
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking snapshot...")
+	snapshot := se.Snapshot()
+	snapshot.SetParams([]float64{5.})
+	if snapshot.Family() != "Clayton" || se.Theta() == 5. {
+		t.Errorf("The snapshot should be independent of the estimator")
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking observation dimension...")
+	if err := se.Update([]float64{0.1, 0.2, 0.3}); err == nil {
+		t.Errorf("The dimension of the observations should not change")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestStreamingTracking(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	before := NewCopula("gumbel", 1.5).sampleWith(2000, 2, rng)
+	after := NewCopula("gumbel", 4.).sampleWith(2000, 2, rng)
+	se, err := NewStreamingEstimator(NewCopula("gumbel", 2.), 0.99, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTitle("Checking streaming tracking...")
+	for i := 0; i < 2000; i++ {
+		se.Update(before.RawRowView(i))
+	}
+	first := se.Theta()
+	for i := 0; i < 2000; i++ {
+		se.Update(after.RawRowView(i))
+	}
+	if math.Abs(first-1.5) > 0.4 || math.Abs(se.Theta()-4.) > 1. {
+		t.Errorf("The estimate should follow the change (%f -> %f)", first, se.Theta())
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking forgetting factor...")
+	if _, err := NewStreamingEstimator(NewCopula("gumbel", 2.), 1.5, 200); err == nil {
+		t.Errorf("The forgetting factor should be lower than 1")
+		testERROR()
+	} else {
+		testOK()
+	}
+}
@@ -75,6 +75,23 @@ func sum(v []float64) float64 {
 	return s
 }
 
+// neumaierSum computes the sum of the values with the Neumaier
+// compensation (the rounding errors are accumulated apart)
+func neumaierSum(v []float64) float64 {
+	s := 0.
+	c := 0.
+	for _, x := range v {
+		t := s + x
+		if math.Abs(s) >= math.Abs(x) {
+			c += (s - t) + x
+		} else {
+			c += (x - t) + s
+		}
+		s = t
+	}
+	return s + c
+}
+
 func mean(v []float64) float64 {
 	s := 0.
 	for _, x := range v {
@@ -27,6 +27,14 @@ func TestSum(t *testing.T) {
 	}
 }
 
+func TestNeumaierSum(t *testing.T) {
+	vector := []float64{1., 1e100, 1., -1e100}
+	s := neumaierSum(vector)
+	if s != 2. {
+		t.Errorf("bad compensated sum computation, expected 2, got %f", s)
+	}
+}
+
 func TestMax(t *testing.T) {
 	vector := []float64{-7., 3.5, 0., 1. / 3.}
 	m := max(vector)
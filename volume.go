@@ -0,0 +1,87 @@
+// volume.go
+
+package gopula
+
+import (
+	"math"
+)
+
+// cornerTerms computes the signed terms of the inclusion-exclusion
+// formula over the corners of a box: the coordinates listed in dims
+// take either their lower or their higher value (the other ones keep
+// the value of vector), f is evaluated at every corner and the sign is
+// negative when an odd number of lower values is taken. The corners
+// having a null coordinate are skipped (the cdf vanishes there).
+func cornerTerms(vector []float64, lower []float64, higher []float64, dims []int, f func([]float64) float64) []float64 {
+	corner := createCopy(vector)
+	terms := make([]float64, 0, 1<<uint(len(dims)))
+	for mask := 0; mask < 1<<uint(len(dims)); mask++ {
+		sign := 1.
+		zero := false
+		for b, j := range dims {
+			if mask&(1<<uint(b)) != 0 {
+				corner[j] = lower[j]
+				sign = -sign
+			} else {
+				corner[j] = higher[j]
+			}
+			zero = zero || corner[j] <= 0.
+		}
+		if zero {
+			continue
+		}
+		terms = append(terms, sign*f(corner))
+	}
+	return terms
+}
+
+// Volume computes the C-volume of the hyper-rectangle (lower, upper],
+// that is to say P(lower < U <= upper), by inclusion-exclusion over its
+// 2^d corners. The signed terms are summed with the Neumaier compensation
+// to limit the cancellation. It returns NaN when the bounds are not valid.
+func (arch *ArchimedeanCopula) Volume(lower []float64, upper []float64) float64 {
+	d := len(lower)
+	if len(upper) != d {
+		return math.NaN()
+	}
+	dims := make([]int, d)
+	for j := range dims {
+		if !(lower[j] >= 0. && lower[j] <= upper[j] && upper[j] <= 1.) {
+			return math.NaN()
+		}
+		dims[j] = j
+	}
+	v := neumaierSum(cornerTerms(lower, lower, upper, dims, arch.Cdf))
+	// rounding errors may lead to tiny negative volumes
+	return math.Min(math.Max(v, 0.), 1.)
+}
+
+// Survival computes the joint exceedance probability P(U > u)
+func (arch *ArchimedeanCopula) Survival(u []float64) float64 {
+	upper := make([]float64, len(u))
+	for j := range upper {
+		upper[j] = 1.
+	}
+	return arch.Volume(u, upper)
+}
+
+// Survival computes the joint exceedance probability P(X > x)
+// (the margins may be discrete)
+func (jd *JointDistribution) Survival(x []float64) float64 {
+	u := make([]float64, len(x))
+	for j, m := range jd.Margins {
+		u[j] = m.CDF(x[j])
+	}
+	return jd.Copula.Survival(u)
+}
+
+// Rectangle computes the probability P(lower < X <= upper)
+// (the margins may be discrete)
+func (jd *JointDistribution) Rectangle(lower []float64, upper []float64) float64 {
+	a := make([]float64, len(lower))
+	b := make([]float64, len(upper))
+	for j, m := range jd.Margins {
+		a[j], b[j] = m.CDF(lower[j]), m.CDF(upper[j])
+	}
+	return jd.Copula.Volume(a, b)
+}
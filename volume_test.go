@@ -0,0 +1,82 @@
+// volume_test.go
+
+package gopula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInitVolume(t *testing.T) {
+	title("C-volumes")
+}
+
+func TestVolume(t *testing.T) {
+	checkTitle("Checking bivariate volume...")
+	AC := NewCopula("gumbel", 2.)
+	a, b := []float64{0.2, 0.3}, []float64{0.7, 0.9}
+	expected := AC.Cdf(b) - AC.Cdf([]float64{a[0], b[1]}) - AC.Cdf([]float64{b[0], a[1]}) + AC.Cdf(a)
+	if v := AC.Volume(a, b); math.Abs(v-expected) > 1e-12 {
+		t.Errorf("Bad volume, expected %f, got %f", expected, v)
+		testERROR()
+	} else {
+		testOK()
+	}
+
+	checkTitle("Checking unit cube volume...")
+	for _, family := range []string{"clayton", "frank", "joe", "amh", "gumbel"} {
+		cop := NewCopula(family, -1.)
+		if v := cop.Volume([]float64{0., 0., 0.}, []float64{1., 1., 1.}); math.Abs(v-1.) > 1e-12 {
+			t.Errorf("Bad volume of the unit cube (%s), expected 1, got %f", family, v)
+			testERROR()
+			return
+		}
+	}
+	testOK()
+
+	checkTitle("Checking invalid volume...")
+	if v := AC.Volume([]float64{0.5, 0.3}, []float64{0.4, 0.9}); !math.IsNaN(v) {
+		t.Errorf("The volume should be NaN, got %f", v)
+		testERROR()
+	} else {
+		testOK()
+	}
+}
+
+func TestSurvival(t *testing.T) {
+	checkTitle("Checking survival function...")
+	AC := NewCopula("clayton", 2.)
+	u := []float64{0.9, 0.95}
+	expected := 1. - u[0] - u[1] + AC.Cdf(u)
+	if s := AC.Survival(u); math.Abs(s-expected) > 1e-12 {
+		t.Errorf("Bad survival, expected %f, got %f", expected, s)
+		testERROR()
+		return
+	}
+	// the volume of tiny boxes in the upper corner cancels a lot
+	// (the gumbel copula is upper tail dependent)
+	tiny := []float64{1. - 1e-5, 1. - 1e-5, 1. - 1e-5}
+	if s := NewCopula("gumbel", 2.).Survival(tiny); !(s > 1e-6 && s < 1e-5) {
+		t.Errorf("Bad survival in the upper corner, got %g", s)
+		testERROR()
+		return
+	}
+	testOK()
+
+	checkTitle("Checking joint survival...")
+	m := NewPoissonMargin(3.)
+	jd := NewJointDistribution(AC, []Margin{m, m})
+	x := []float64{4., 5.}
+	expected = AC.Survival([]float64{m.CDF(4.), m.CDF(5.)})
+	if s := jd.Survival(x); math.Abs(s-expected) > 1e-12 {
+		t.Errorf("Bad joint survival, expected %f, got %f", expected, s)
+		testERROR()
+		return
+	}
+	if r := jd.Rectangle([]float64{-1., -1.}, x); math.Abs(r-jd.Cdf(x)) > 1e-12 {
+		t.Errorf("Bad rectangle probability, expected %f, got %f", jd.Cdf(x), r)
+		testERROR()
+		return
+	}
+	testOK()
+}